@@ -0,0 +1,129 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// how long a rule stays quiet for a game after firing, unless the rule sets its own cooldown
+const defaultCooldown = time.Hour
+
+// Alert is what notifiers deliver when a rule fires.
+type Alert struct {
+	RuleID        string    `json:"rule_id"`
+	GameID        string    `json:"game_id"`
+	HomeTeam      string    `json:"home_team"`
+	AwayTeam      string    `json:"away_team"`
+	Metric        string    `json:"metric"`
+	Direction     string    `json:"direction"`
+	Threshold     float64   `json:"threshold"`
+	Value         string    `json:"value"`
+	PreviousValue string    `json:"previous_value,omitempty"`
+	TriggeredAt   time.Time `json:"triggered_at"`
+}
+
+// Summary describes the alert in a sentence.
+func (a Alert) Summary() string {
+	return fmt.Sprintf("%s for %s vs %s is now %s (%s %g)", a.Metric, a.HomeTeam, a.AwayTeam, a.Value, a.Direction, a.Threshold)
+}
+
+// Evaluator checks game updates against the stored rules and delivers the alerts that fire.
+type Evaluator struct {
+	Rules     RulesManager
+	Notifiers map[string]Notifier // by channel name
+}
+
+// HasChannel reports whether a notifier is configured for channel.
+func (e *Evaluator) HasChannel(channel string) bool {
+	_, ok := e.Notifiers[channel]
+	return ok
+}
+
+// ValidateTarget checks a rule's target with its channel's notifier, for notifiers that
+// can tell a bad target before delivering to it.
+func (e *Evaluator) ValidateTarget(ctx context.Context, channel, target string) error {
+	validator, ok := e.Notifiers[channel].(TargetValidator)
+	if !ok {
+		return nil
+	}
+	return validator.ValidateTarget(ctx, target)
+}
+
+// Evaluate fires the rules watching a game whose metric crossed its threshold between
+// previous and current. A rule only fires on the crossing, not on every update while the
+// metric stays past the threshold, and then not again for that game until its cooldown ends.
+// Deliveries happen in the background so a slow notifier doesn't hold up the receiver.
+func (e *Evaluator) Evaluate(ctx context.Context, gameID string, previous, current map[string]string) {
+	rules, err := e.Rules.RulesForGame(ctx, gameID, current["home_team"], current["away_team"])
+	if err != nil {
+		slog.ErrorContext(ctx, "error getting alert rules", "game_id", gameID, "err", err)
+		return
+	}
+
+	for _, rule := range rules {
+		value, ok := parseMetric(current[rule.Metric])
+		if !ok || !rule.matches(value) {
+			continue
+		}
+		if previousValue, ok := parseMetric(previous[rule.Metric]); ok && rule.matches(previousValue) {
+			continue
+		}
+
+		// a rule whose channel isn't configured anymore mustn't use up its cooldown
+		notifier, ok := e.Notifiers[rule.Channel]
+		if !ok {
+			slog.WarnContext(ctx, "no notifier configured for alert rule", "channel", rule.Channel, "rule_id", rule.ID)
+			continue
+		}
+
+		cooldown := defaultCooldown
+		if rule.CooldownSeconds > 0 {
+			cooldown = time.Duration(rule.CooldownSeconds) * time.Second
+		}
+		fire, err := e.Rules.StartCooldown(ctx, rule.ID, gameID, cooldown)
+		if err != nil {
+			slog.ErrorContext(ctx, "error checking alert cooldown", "rule_id", rule.ID, "err", err)
+			continue
+		}
+		if !fire {
+			continue
+		}
+
+		alert := Alert{
+			RuleID:        rule.ID,
+			GameID:        gameID,
+			HomeTeam:      current["home_team"],
+			AwayTeam:      current["away_team"],
+			Metric:        rule.Metric,
+			Direction:     rule.Direction,
+			Threshold:     rule.Threshold,
+			Value:         current[rule.Metric],
+			PreviousValue: previous[rule.Metric],
+			TriggeredAt:   time.Now().UTC(),
+		}
+		go func(target string) {
+			// the receiver's context lives as long as the consumer, bound each delivery instead
+			deliverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			defer cancel()
+			if err := notifier.Notify(deliverCtx, target, alert); err != nil {
+				slog.ErrorContext(deliverCtx, "error delivering alert", "rule_id", alert.RuleID, "err", err)
+			}
+		}(rule.Target)
+	}
+}
+
+// parseMetric turns stored values like "$99.00" or "+135" into numbers.
+func parseMetric(value string) (float64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(strings.TrimPrefix(value, "$"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
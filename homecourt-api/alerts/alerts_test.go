@@ -0,0 +1,168 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeRules keeps rules and cooldowns in memory.
+type fakeRules struct {
+	rules     []Rule
+	cooldowns map[string]bool
+}
+
+func (f *fakeRules) CreateRule(ctx context.Context, rule Rule) (Rule, error) {
+	f.rules = append(f.rules, rule)
+	return rule, nil
+}
+
+func (f *fakeRules) GetRule(ctx context.Context, ruleID string) (Rule, error) {
+	for _, rule := range f.rules {
+		if rule.ID == ruleID {
+			return rule, nil
+		}
+	}
+	return Rule{}, ErrRuleNotFound
+}
+
+func (f *fakeRules) ListRules(ctx context.Context, ownerID string) ([]Rule, error) {
+	var rules []Rule
+	for _, rule := range f.rules {
+		if rule.OwnerID == ownerID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (f *fakeRules) DeleteRule(ctx context.Context, ruleID string) error {
+	return nil
+}
+
+func (f *fakeRules) RulesForGame(ctx context.Context, gameID, homeTeam, awayTeam string) ([]Rule, error) {
+	var rules []Rule
+	for _, rule := range f.rules {
+		if rule.GameID == gameID || rule.Team == homeTeam || rule.Team == awayTeam {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (f *fakeRules) StartCooldown(ctx context.Context, ruleID, gameID string, cooldown time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s:%s", ruleID, gameID)
+	if f.cooldowns[key] {
+		return false, nil
+	}
+	f.cooldowns[key] = true
+	return true, nil
+}
+
+// chanNotifier hands every alert it's given to the test.
+type chanNotifier chan Alert
+
+func (n chanNotifier) Notify(ctx context.Context, target string, alert Alert) error {
+	n <- alert
+	return nil
+}
+
+func newEvaluator(rules ...Rule) (*Evaluator, *fakeRules, chanNotifier) {
+	fake := &fakeRules{rules: rules, cooldowns: map[string]bool{}}
+	notifier := make(chanNotifier, 10)
+	return &Evaluator{Rules: fake, Notifiers: map[string]Notifier{"test": notifier}}, fake, notifier
+}
+
+func game(price string) map[string]string {
+	return map[string]string{"home_team": "DAL", "away_team": "NYK", MetricTicketPrice: price}
+}
+
+// expectAlerts waits for n alerts and then makes sure no more arrive.
+func expectAlerts(t *testing.T, notifier chanNotifier, n int) []Alert {
+	t.Helper()
+	var alerts []Alert
+	for len(alerts) < n {
+		select {
+		case alert := <-notifier:
+			alerts = append(alerts, alert)
+		case <-time.After(time.Second):
+			t.Fatalf("got %d alerts, want %d", len(alerts), n)
+		}
+	}
+	select {
+	case alert := <-notifier:
+		t.Fatalf("unexpected alert: %+v", alert)
+	case <-time.After(50 * time.Millisecond):
+	}
+	return alerts
+}
+
+func TestEvaluateFiresOnCrossing(t *testing.T) {
+	e, _, notifier := newEvaluator(Rule{ID: "r1", Team: "DAL", Metric: MetricTicketPrice, Direction: Below, Threshold: 50, Channel: "test"})
+
+	e.Evaluate(context.Background(), "g1", game("$60.00"), game("$45.00"))
+	alerts := expectAlerts(t, notifier, 1)
+	if alerts[0].Value != "$45.00" || alerts[0].PreviousValue != "$60.00" || alerts[0].GameID != "g1" {
+		t.Errorf("unexpected alert: %+v", alerts[0])
+	}
+
+	// still below, that's not a new crossing
+	e.Evaluate(context.Background(), "g1", game("$45.00"), game("$40.00"))
+	expectAlerts(t, notifier, 0)
+}
+
+func TestEvaluateIgnoresOtherDirectionAndMissingValues(t *testing.T) {
+	e, _, notifier := newEvaluator(Rule{ID: "r1", GameID: "g1", Metric: MetricTicketPrice, Direction: Above, Threshold: 100, Channel: "test"})
+
+	e.Evaluate(context.Background(), "g1", game("$60.00"), game("$45.00"))
+	e.Evaluate(context.Background(), "g1", game("$60.00"), game(""))
+	expectAlerts(t, notifier, 0)
+
+	// nothing known before counts as a crossing
+	e.Evaluate(context.Background(), "g1", game(""), game("$120.00"))
+	expectAlerts(t, notifier, 1)
+}
+
+func TestEvaluateCooldown(t *testing.T) {
+	e, _, notifier := newEvaluator(Rule{ID: "r1", Team: "NYK", Metric: MetricTicketPrice, Direction: Below, Threshold: 50, Channel: "test"})
+
+	e.Evaluate(context.Background(), "g1", game("$60.00"), game("$45.00"))
+	e.Evaluate(context.Background(), "g1", game("$45.00"), game("$55.00"))
+	e.Evaluate(context.Background(), "g1", game("$55.00"), game("$48.00"))
+	expectAlerts(t, notifier, 1)
+
+	// the cooldown is per game
+	e.Evaluate(context.Background(), "g2", game("$60.00"), game("$45.00"))
+	expectAlerts(t, notifier, 1)
+}
+
+func TestEvaluateWithoutNotifierKeepsCooldown(t *testing.T) {
+	e, fake, notifier := newEvaluator(Rule{ID: "r1", Team: "DAL", Metric: MetricTicketPrice, Direction: Below, Threshold: 50, Channel: "smtp"})
+
+	e.Evaluate(context.Background(), "g1", game("$60.00"), game("$45.00"))
+	expectAlerts(t, notifier, 0)
+	if len(fake.cooldowns) != 0 {
+		t.Errorf("cooldown started for a rule that can't be delivered: %v", fake.cooldowns)
+	}
+}
+
+func TestParseMetric(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"$99.00", 99, true},
+		{"+135", 135, true},
+		{"-190", -190, true},
+		{"", 0, false},
+		{"N/A", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseMetric(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseMetric(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
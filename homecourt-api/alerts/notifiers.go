@@ -0,0 +1,108 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"homecourt-api/outbound"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Notifier delivers a fired alert to a rule's target.
+type Notifier interface {
+	Notify(ctx context.Context, target string, alert Alert) error
+}
+
+// TargetValidator is implemented by notifiers that check targets when a rule is created.
+type TargetValidator interface {
+	ValidateTarget(ctx context.Context, target string) error
+}
+
+// LogNotifier writes alerts to the server log. Its target is ignored.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, target string, alert Alert) error {
+	slog.InfoContext(ctx, "alert fired", "rule_id", alert.RuleID, "summary", alert.Summary())
+	return nil
+}
+
+// WebhookNotifier POSTs the alert as JSON to the target URL. Targets on internal addresses
+// are refused, both when the rule is created and when the alert is delivered.
+type WebhookNotifier struct {
+	Client *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{
+		Client: outbound.NewClient(10 * time.Second),
+	}
+}
+
+func (n *WebhookNotifier) ValidateTarget(ctx context.Context, target string) error {
+	return outbound.CheckURL(ctx, target)
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, target string, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Homecourt/1.0")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook to %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d from webhook %s", resp.StatusCode, target)
+	}
+	return nil
+}
+
+// SMTPNotifier emails the alert to the target address.
+type SMTPNotifier struct {
+	Addr string // host:port of the SMTP server
+	From string
+	Auth smtp.Auth // nil for servers that don't require auth
+}
+
+func (n *SMTPNotifier) ValidateTarget(ctx context.Context, target string) error {
+	addr, err := mail.ParseAddress(target)
+	if err != nil || addr.Address != target {
+		return fmt.Errorf("invalid email address: %q", target)
+	}
+	return nil
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, target string, alert Alert) error {
+	if strings.ContainsAny(target, "\r\n") {
+		return fmt.Errorf("invalid email address: %q", target)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", target)
+	fmt.Fprintf(&msg, "Subject: Homecourt alert: %s vs %s\r\n", alert.HomeTeam, alert.AwayTeam)
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(&msg, "\r\n%s\r\n", alert.Summary())
+
+	err := smtp.SendMail(n.Addr, n.Auth, n.From, []string{target}, msg.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to send alert email to %s: %v", target, err)
+	}
+	return nil
+}
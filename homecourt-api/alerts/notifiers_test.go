@@ -0,0 +1,224 @@
+package alerts
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"homecourt-api/outbound"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testAlert = Alert{
+	RuleID:      "r1",
+	GameID:      "g1",
+	HomeTeam:    "DAL",
+	AwayTeam:    "NYK",
+	Metric:      MetricTicketPrice,
+	Direction:   Below,
+	Threshold:   50,
+	Value:       "$45.00",
+	TriggeredAt: time.Date(2024, 11, 21, 9, 30, 2, 0, time.UTC),
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	received := make(chan Alert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		received <- alert
+	}))
+	defer server.Close()
+
+	// the test server is on loopback, which NewWebhookNotifier's client refuses
+	n := &WebhookNotifier{Client: server.Client()}
+	if err := n.Notify(context.Background(), server.URL, testAlert); err != nil {
+		t.Fatal(err)
+	}
+	if alert := <-received; alert != testAlert {
+		t.Errorf("got %+v, want %+v", alert, testAlert)
+	}
+}
+
+func TestWebhookNotifierStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := &WebhookNotifier{Client: server.Client()}
+	if err := n.Notify(context.Background(), server.URL, testAlert); err == nil {
+		t.Error("expected an error for a 500")
+	}
+}
+
+func TestWebhookNotifierRefusesInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier()
+	err := n.Notify(context.Background(), server.URL, testAlert)
+	if !errors.Is(err, outbound.ErrForbiddenAddress) {
+		t.Errorf("got %v, want %v", err, outbound.ErrForbiddenAddress)
+	}
+	if called {
+		t.Error("webhook delivered to a loopback address")
+	}
+}
+
+func TestWebhookNotifierValidateTarget(t *testing.T) {
+	n := NewWebhookNotifier()
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/hook",
+		"http://[fd00::1]/hook",
+		"ftp://example.com/hook",
+		"/relative",
+	} {
+		if err := n.ValidateTarget(context.Background(), target); err == nil {
+			t.Errorf("ValidateTarget(%q) accepted an internal or invalid target", target)
+		}
+	}
+	if err := n.ValidateTarget(context.Background(), "https://93.184.215.14/hook"); err != nil {
+		t.Errorf("ValidateTarget rejected a public address: %v", err)
+	}
+}
+
+// smtpStub accepts a single message over plain SMTP and hands its envelope and data to
+// the test.
+type smtpStub struct {
+	addr     string
+	messages chan smtpMessage
+}
+
+type smtpMessage struct {
+	from, to, data string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	stub := &smtpStub{addr: listener.Addr().String(), messages: make(chan smtpMessage, 1)}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		stub.serve(conn)
+	}()
+	return stub
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	var msg smtpMessage
+	reply("220 localhost ESMTP stub")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			msg.to = strings.Trim(line[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			msg.data = data.String()
+			reply("250 OK")
+			s.messages <- msg
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	stub := newSMTPStub(t)
+	n := &SMTPNotifier{Addr: stub.addr, From: "alerts@homecourt.example"}
+
+	if err := n.Notify(context.Background(), "fan@example.com", testAlert); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-stub.messages
+	if msg.from != "alerts@homecourt.example" || msg.to != "fan@example.com" {
+		t.Errorf("unexpected envelope: from %q to %q", msg.from, msg.to)
+	}
+	if !strings.Contains(msg.data, "Subject: Homecourt alert: DAL vs NYK\r\n") {
+		t.Errorf("missing subject in:\n%s", msg.data)
+	}
+	if !strings.Contains(msg.data, testAlert.Summary()) {
+		t.Errorf("missing summary in:\n%s", msg.data)
+	}
+}
+
+func TestSMTPNotifierRejectsHeaderInjection(t *testing.T) {
+	n := &SMTPNotifier{Addr: "127.0.0.1:1", From: "alerts@homecourt.example"}
+	target := "fan@example.com\r\nBcc: everyone@example.com"
+
+	if err := n.ValidateTarget(context.Background(), target); err == nil {
+		t.Error("ValidateTarget accepted a target with a header in it")
+	}
+	if err := n.Notify(context.Background(), target, testAlert); err == nil {
+		t.Error("Notify sent to a target with a header in it")
+	}
+}
+
+func TestSMTPNotifierValidateTarget(t *testing.T) {
+	n := &SMTPNotifier{}
+	if err := n.ValidateTarget(context.Background(), "fan@example.com"); err != nil {
+		t.Errorf("rejected a plain address: %v", err)
+	}
+	for _, target := range []string{"", "not an address", "Fan <fan@example.com>"} {
+		if err := n.ValidateTarget(context.Background(), target); err == nil {
+			t.Errorf("ValidateTarget(%q) accepted an invalid address", target)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package alerts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"homecourt-api/metrics"
+	"homecourt-api/tracing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Metrics a rule can watch. They're the game hash fields the receiver writes.
+const (
+	MetricTicketPrice  = "lowest_ticket_price"
+	MetricHomeTeamOdds = "home_team_odds"
+)
+
+const (
+	Below = "below"
+	Above = "above"
+)
+
+// ErrRuleNotFound is returned when a rule ID doesn't exist.
+var ErrRuleNotFound = errors.New("alert rule not found")
+
+// Rule fires when metric on a watched game crosses threshold in direction.
+// A rule watches either every home and away game of Team or the single game GameID.
+// Rules belong to the user who created them, only they can see or delete them.
+type Rule struct {
+	ID              string    `json:"id"`
+	OwnerID         string    `json:"owner_id"`
+	Team            string    `json:"team,omitempty"`
+	GameID          string    `json:"game_id,omitempty"`
+	Metric          string    `json:"metric"`
+	Threshold       float64   `json:"threshold"`
+	Direction       string    `json:"direction"`
+	Channel         string    `json:"channel"` // name of the notifier, e.g. "webhook"
+	Target          string    `json:"target"`  // where the notifier delivers, e.g. a URL or email address
+	CooldownSeconds int       `json:"cooldown_seconds,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Validate checks the parts of a rule that don't depend on configuration.
+func (r Rule) Validate() error {
+	if (r.Team == "") == (r.GameID == "") {
+		return fmt.Errorf("exactly one of team or game_id is required")
+	}
+	if r.Metric != MetricTicketPrice && r.Metric != MetricHomeTeamOdds {
+		return fmt.Errorf("unknown metric: %s", r.Metric)
+	}
+	if r.Direction != Below && r.Direction != Above {
+		return fmt.Errorf("direction must be %q or %q", Below, Above)
+	}
+	if r.Channel == "" {
+		return fmt.Errorf("channel is required")
+	}
+	if r.CooldownSeconds < 0 {
+		return fmt.Errorf("cooldown_seconds can't be negative")
+	}
+	return nil
+}
+
+// matches reports whether value is past the rule's threshold.
+func (r Rule) matches(value float64) bool {
+	if r.Direction == Below {
+		return value < r.Threshold
+	}
+	return value > r.Threshold
+}
+
+// RulesManager defines the methods for storing alert rules.
+type RulesManager interface {
+	CreateRule(ctx context.Context, rule Rule) (Rule, error)
+	GetRule(ctx context.Context, ruleID string) (Rule, error)
+	ListRules(ctx context.Context, ownerID string) ([]Rule, error)
+	DeleteRule(ctx context.Context, ruleID string) error
+	RulesForGame(ctx context.Context, gameID, homeTeam, awayTeam string) ([]Rule, error)
+	// StartCooldown records that ruleID fired for gameID. It returns false if the
+	// rule is still cooling down from an earlier alert for that game.
+	StartCooldown(ctx context.Context, ruleID, gameID string, cooldown time.Duration) (bool, error)
+}
+
+// redisRulesManager stores rules as JSON strings, indexed by team and game and by owner.
+type redisRulesManager struct {
+	client *redis.Client
+}
+
+func NewRulesManager(addr string) (RulesManager, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return &redisRulesManager{client: client}, nil
+}
+
+func ruleKey(ruleID string) string {
+	return fmt.Sprintf("alert:rule:%s", ruleID)
+}
+
+// indexKey is the set of rule IDs watching a team or a game.
+func indexKey(rule Rule) string {
+	if rule.GameID != "" {
+		return fmt.Sprintf("alerts:game:%s", rule.GameID)
+	}
+	return fmt.Sprintf("alerts:team:%s", rule.Team)
+}
+
+// ownerKey is the set of rule IDs a user created.
+func ownerKey(ownerID string) string {
+	return fmt.Sprintf("alerts:owner:%s", ownerID)
+}
+
+func newRuleID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (r *redisRulesManager) CreateRule(ctx context.Context, rule Rule) (Rule, error) {
+	id, err := newRuleID()
+	if err != nil {
+		return Rule{}, fmt.Errorf("failed to generate rule id: %v", err)
+	}
+	rule.ID = id
+	rule.CreatedAt = time.Now().UTC()
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return Rule{}, fmt.Errorf("failed to marshal rule: %v", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, ruleKey(rule.ID), data, 0)
+		pipe.SAdd(ctx, indexKey(rule), rule.ID)
+		pipe.SAdd(ctx, ownerKey(rule.OwnerID), rule.ID)
+		pipe.SAdd(ctx, "alerts:rules", rule.ID)
+		return nil
+	})
+	if err != nil {
+		return Rule{}, fmt.Errorf("failed to store rule: %v", err)
+	}
+	return rule, nil
+}
+
+func (r *redisRulesManager) GetRule(ctx context.Context, ruleID string) (Rule, error) {
+	data, err := r.client.Get(ctx, ruleKey(ruleID)).Bytes()
+	if err == redis.Nil {
+		return Rule{}, ErrRuleNotFound
+	}
+	if err != nil {
+		return Rule{}, fmt.Errorf("failed to get rule %s: %v", ruleID, err)
+	}
+
+	var rule Rule
+	err = json.Unmarshal(data, &rule)
+	if err != nil {
+		return Rule{}, fmt.Errorf("failed to unmarshal rule %s: %v", ruleID, err)
+	}
+	return rule, nil
+}
+
+func (r *redisRulesManager) ListRules(ctx context.Context, ownerID string) ([]Rule, error) {
+	ruleIDs, err := r.client.SMembers(ctx, ownerKey(ownerID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules: %v", err)
+	}
+	return r.getRules(ctx, ruleIDs)
+}
+
+func (r *redisRulesManager) DeleteRule(ctx context.Context, ruleID string) error {
+	rule, err := r.GetRule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, ruleKey(ruleID))
+		pipe.SRem(ctx, indexKey(rule), ruleID)
+		pipe.SRem(ctx, ownerKey(rule.OwnerID), ruleID)
+		pipe.SRem(ctx, "alerts:rules", ruleID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete rule %s: %v", ruleID, err)
+	}
+	return nil
+}
+
+func (r *redisRulesManager) RulesForGame(ctx context.Context, gameID, homeTeam, awayTeam string) ([]Rule, error) {
+	ruleIDs, err := r.client.SUnion(ctx,
+		indexKey(Rule{GameID: gameID}),
+		indexKey(Rule{Team: homeTeam}),
+		indexKey(Rule{Team: awayTeam}),
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules for game %s: %v", gameID, err)
+	}
+	return r.getRules(ctx, ruleIDs)
+}
+
+func (r *redisRulesManager) getRules(ctx context.Context, ruleIDs []string) ([]Rule, error) {
+	rules := []Rule{}
+	for _, ruleID := range ruleIDs {
+		rule, err := r.GetRule(ctx, ruleID)
+		// deleted between reading the index and the rule
+		if err == ErrRuleNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r *redisRulesManager) StartCooldown(ctx context.Context, ruleID, gameID string, cooldown time.Duration) (bool, error) {
+	key := fmt.Sprintf("alert:cooldown:%s:%s", ruleID, gameID)
+	// SET NX makes this safe when several replicas evaluate the same update
+	ok, err := r.client.SetNX(ctx, key, time.Now().UTC().Format(time.RFC3339), cooldown).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to start cooldown for rule %s: %v", ruleID, err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,241 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"homecourt-api/metrics"
+	"homecourt-api/ratelimit"
+	"homecourt-api/tracing"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// how long daily usage counters are kept
+const usageRetention = 90 * 24 * time.Hour
+
+var ErrKeyNotFound = errors.New("api key not found")
+
+// Key is an API key issued to a client. The key itself is only known to the client,
+// it's stored as a SHA-256 hash.
+type Key struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"` // who the key was issued to
+	Limit     ratelimit.Limit `json:"limit"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Usage is a key's request counts for one UTC day, by route and by status class ("2xx", "4xx", ...).
+type Usage struct {
+	Date     string           `json:"date"`
+	Routes   map[string]int64 `json:"routes"`
+	Statuses map[string]int64 `json:"statuses"`
+}
+
+// KeysManager defines the methods for issuing API keys and tracking their usage.
+type KeysManager interface {
+	CreateKey(ctx context.Context, name string, limit ratelimit.Limit) (Key, string, error)
+	GetKey(ctx context.Context, keyID string) (Key, error)
+	GetKeyBySecret(ctx context.Context, secret string) (Key, error)
+	ListKeys(ctx context.Context) ([]Key, error)
+	RevokeKey(ctx context.Context, keyID string) error
+	RecordUsage(ctx context.Context, keyID, route string, status int) error
+	GetUsage(ctx context.Context, keyID string, days int) ([]Usage, error)
+}
+
+type redisKeysManager struct {
+	client *redis.Client
+}
+
+func NewKeysManager(addr string) (KeysManager, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return &redisKeysManager{client: client}, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func keyKey(keyID string) string {
+	return fmt.Sprintf("apikey:%s", keyID)
+}
+
+// secretKey maps the hash of a key's secret to its ID.
+func secretKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return fmt.Sprintf("apikey:secret:%s", hex.EncodeToString(sum[:]))
+}
+
+func usageKey(keyID string, day time.Time) string {
+	return fmt.Sprintf("apikey:%s:usage:%s", keyID, day.Format("2006-01-02"))
+}
+
+// CreateKey issues a key and returns it along with its secret, which can't be recovered later.
+func (r *redisKeysManager) CreateKey(ctx context.Context, name string, limit ratelimit.Limit) (Key, string, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return Key{}, "", fmt.Errorf("failed to generate api key id: %v", err)
+	}
+	random, err := randomHex(24)
+	if err != nil {
+		return Key{}, "", fmt.Errorf("failed to generate api key: %v", err)
+	}
+	secret := "hc_" + random
+
+	key := Key{
+		ID:        id,
+		Name:      name,
+		Limit:     limit,
+		CreatedAt: time.Now().UTC(),
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		return Key{}, "", fmt.Errorf("failed to marshal api key: %v", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, keyKey(key.ID), data, 0)
+		// both directions, so revoking by ID can find the secret's entry
+		pipe.Set(ctx, keyKey(key.ID)+":secret", secretKey(secret), 0)
+		pipe.Set(ctx, secretKey(secret), key.ID, 0)
+		pipe.SAdd(ctx, "apikeys", key.ID)
+		return nil
+	})
+	if err != nil {
+		return Key{}, "", fmt.Errorf("failed to store api key: %v", err)
+	}
+	return key, secret, nil
+}
+
+func (r *redisKeysManager) GetKey(ctx context.Context, keyID string) (Key, error) {
+	data, err := r.client.Get(ctx, keyKey(keyID)).Bytes()
+	if err == redis.Nil {
+		return Key{}, ErrKeyNotFound
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to get api key %s: %v", keyID, err)
+	}
+
+	var key Key
+	err = json.Unmarshal(data, &key)
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to unmarshal api key %s: %v", keyID, err)
+	}
+	return key, nil
+}
+
+func (r *redisKeysManager) GetKeyBySecret(ctx context.Context, secret string) (Key, error) {
+	keyID, err := r.client.Get(ctx, secretKey(secret)).Result()
+	if err == redis.Nil {
+		return Key{}, ErrKeyNotFound
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to look up api key: %v", err)
+	}
+	return r.GetKey(ctx, keyID)
+}
+
+func (r *redisKeysManager) ListKeys(ctx context.Context) ([]Key, error) {
+	keyIDs, err := r.client.SMembers(ctx, "apikeys").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %v", err)
+	}
+
+	keys := []Key{}
+	for _, keyID := range keyIDs {
+		key, err := r.GetKey(ctx, keyID)
+		// revoked between reading the set and the key
+		if err == ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (r *redisKeysManager) RevokeKey(ctx context.Context, keyID string) error {
+	secretHashKey, err := r.client.Get(ctx, keyKey(keyID)+":secret").Result()
+	if err == redis.Nil {
+		return ErrKeyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get api key %s: %v", keyID, err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keyKey(keyID), keyKey(keyID)+":secret", secretHashKey)
+		pipe.SRem(ctx, "apikeys", keyID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key %s: %v", keyID, err)
+	}
+	return nil
+}
+
+func (r *redisKeysManager) RecordUsage(ctx context.Context, keyID, route string, status int) error {
+	key := usageKey(keyID, time.Now().UTC())
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, key, "route:"+route, 1)
+		pipe.HIncrBy(ctx, key, fmt.Sprintf("status:%dxx", status/100), 1)
+		pipe.Expire(ctx, key, usageRetention)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record usage for api key %s: %v", keyID, err)
+	}
+	return nil
+}
+
+// GetUsage returns a key's usage for the last days UTC days, today first.
+func (r *redisKeysManager) GetUsage(ctx context.Context, keyID string, days int) ([]Usage, error) {
+	usage := []Usage{}
+	today := time.Now().UTC()
+	for i := 0; i < days; i++ {
+		day := today.AddDate(0, 0, -i)
+		counts, err := r.client.HGetAll(ctx, usageKey(keyID, day)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage for api key %s: %v", keyID, err)
+		}
+
+		daily := Usage{
+			Date:     day.Format("2006-01-02"),
+			Routes:   make(map[string]int64),
+			Statuses: make(map[string]int64),
+		}
+		for field, count := range counts {
+			n, _ := strconv.ParseInt(count, 10, 64)
+			if route, ok := strings.CutPrefix(field, "route:"); ok {
+				daily.Routes[route] = n
+			} else if status, ok := strings.CutPrefix(field, "status:"); ok {
+				daily.Statuses[status] = n
+			}
+		}
+		usage = append(usage, daily)
+	}
+	return usage, nil
+}
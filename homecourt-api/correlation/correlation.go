@@ -0,0 +1,27 @@
+package correlation
+
+import (
+	"context"
+	"homecourt-api/requestid"
+)
+
+// Header is the AMQP header homecourt-stream puts the correlation ID in. Every message
+// published from one provider fetch carries the same ID.
+const Header = "correlation_id"
+
+type contextKey struct{}
+
+// New returns a random correlation ID, for messages that arrived without one.
+func New() string {
+	return requestid.New()
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the correlation ID of ctx, "" if it has none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,176 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"homecourt-api/metrics"
+	"homecourt-api/tracing"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Event types pushed to subscribers whenever the receiver changes a game. InjuryUpdated is
+// reserved for when the receiver handles the injury feed, nothing emits it yet.
+const (
+	PriceChanged  = "game.price_changed"
+	OddsChanged   = "game.odds_changed"
+	InjuryUpdated = "game.injury_updated"
+	Rescheduled   = "game.rescheduled"
+	StatusChanged = "game.status_changed"
+)
+
+const (
+	redisChannel   = "homecourt:game_events"
+	subscriberSize = 16 // events buffered per subscriber before they start getting dropped
+)
+
+// GameEvent describes a change to a single game.
+type GameEvent struct {
+	Type          string            `json:"type"`
+	GameID        string            `json:"game_id"`
+	HomeTeam      string            `json:"home_team"`
+	AwayTeam      string            `json:"away_team"`
+	Changes       map[string]string `json:"changes"` // changed fields and their new values
+	Timestamp     time.Time         `json:"timestamp"`
+	CorrelationID string            `json:"correlation_id,omitempty"` // the provider fetch that caused the change
+}
+
+// Involves reports whether team is playing in the game.
+func (e GameEvent) Involves(team string) bool {
+	return e.HomeTeam == team || e.AwayTeam == team
+}
+
+type subscriber struct {
+	teams  map[string]bool // empty means every team
+	events chan GameEvent
+}
+
+// Hub fans game events out to local subscribers. Events go through Redis pub/sub so that
+// subscribers on every replica see changes made by any replica's receiver.
+type Hub struct {
+	client *redis.Client
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+func NewHub(addr string) (*Hub, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return &Hub{
+		client:      client,
+		subscribers: make(map[*subscriber]struct{}),
+	}, nil
+}
+
+// Publish sends an event to the subscribers of every replica.
+func (h *Hub) Publish(ctx context.Context, event GameEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %v", event.Type, err)
+	}
+	err = h.client.Publish(ctx, redisChannel, payload).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish %s event for %s: %v", event.Type, event.GameID, err)
+	}
+	return nil
+}
+
+// Run relays events from Redis to local subscribers until ctx is cancelled.
+func (h *Hub) Run(ctx context.Context) {
+	pubsub := h.client.Subscribe(ctx, redisChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			h.Close()
+			slog.Info("event hub has been stopped")
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event GameEvent
+			err := json.Unmarshal([]byte(message.Payload), &event)
+			if err != nil {
+				slog.Error("error parsing game event", "err", err)
+				continue
+			}
+			h.broadcast(event)
+		}
+	}
+}
+
+func (h *Hub) broadcast(event GameEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if len(sub.teams) > 0 && !sub.teams[event.HomeTeam] && !sub.teams[event.AwayTeam] {
+			continue
+		}
+		// a slow client must not hold up everyone else
+		select {
+		case sub.events <- event:
+		default:
+			slog.Warn("dropping event, subscriber is falling behind", "type", event.Type, "game_id", event.GameID)
+		}
+	}
+}
+
+// Subscribe registers interest in games involving any of teams (all games when teams is empty).
+// The returned channel is closed when unsubscribe is called or the hub shuts down.
+func (h *Hub) Subscribe(teams []string) (events <-chan GameEvent, unsubscribe func()) {
+	sub := &subscriber{
+		teams:  make(map[string]bool),
+		events: make(chan GameEvent, subscriberSize),
+	}
+	for _, team := range teams {
+		sub.teams[team] = true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.events)
+		return sub.events, func() {}
+	}
+	h.subscribers[sub] = struct{}{}
+
+	return sub.events, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[sub]; ok {
+			delete(h.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Close disconnects every subscriber. Long-lived streams would otherwise block server shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
@@ -0,0 +1,259 @@
+package games
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"homecourt-api/metrics"
+	"homecourt-api/tracing"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+// entries are dropped after this even without an invalidation, which can be missed while the
+// subscription reconnects or when a game is written without CreateOrUpdateGame
+const cacheTTL = 30 * time.Second
+
+var (
+	cacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "homecourt_games_cache_hits_total",
+		Help: "Games read from the in-memory cache.",
+	})
+	cacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "homecourt_games_cache_misses_total",
+		Help: "Games read through to Redis, missing or expired in the cache.",
+	})
+	cacheInvalidations = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "homecourt_games_cache_invalidations_total",
+		Help: "Games dropped from the cache because a replica changed them.",
+	})
+)
+
+type cacheEntry struct {
+	key     string
+	game    map[string]string
+	expires time.Time
+}
+
+// CachedGamesManager keeps the most recently read games in memory in front of another
+// GamesManager. Entries are dropped as soon as any replica changes the game, through
+// InvalidationChannel, so only start Run before serving from it.
+type CachedGamesManager struct {
+	GamesManager
+	client *redis.Client
+	size   int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // game hash key to its element in lru
+	lru     *list.List               // most recently used first
+	// bumped by every invalidation, a read that overlapped one isn't cached since it may
+	// have fetched the game from before the change
+	generation uint64
+}
+
+func NewCachedGamesManager(manager GamesManager, addr string, size int) (*CachedGamesManager, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return &CachedGamesManager{
+		GamesManager: manager,
+		client:       client,
+		size:         size,
+		entries:      make(map[string]*list.Element),
+		lru:          list.New(),
+	}, nil
+}
+
+// Run drops games from the cache as they change until ctx is cancelled.
+func (c *CachedGamesManager) Run(ctx context.Context) {
+	pubsub := c.client.Subscribe(ctx, InvalidationChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("games cache has been stopped")
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			c.invalidate(message.Payload)
+		}
+	}
+}
+
+func (c *CachedGamesManager) invalidate(gameKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if element, ok := c.entries[gameKey]; ok {
+		c.lru.Remove(element)
+		delete(c.entries, gameKey)
+		cacheInvalidations.Inc()
+	}
+}
+
+// get returns a copy of the cached game at gameKey, callers are free to modify it.
+// generation is what to pass to put after fetching a game that wasn't cached.
+func (c *CachedGamesManager) get(gameKey string) (game map[string]string, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[gameKey]
+	if !ok {
+		cacheMisses.Inc()
+		return nil, c.generation
+	}
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.lru.Remove(element)
+		delete(c.entries, gameKey)
+		cacheMisses.Inc()
+		return nil, c.generation
+	}
+	c.lru.MoveToFront(element)
+	cacheHits.Inc()
+	return maps.Clone(entry.game), c.generation
+}
+
+func (c *CachedGamesManager) put(gameKey string, game map[string]string, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	entry := &cacheEntry{key: gameKey, game: maps.Clone(game), expires: time.Now().Add(cacheTTL)}
+	if element, ok := c.entries[gameKey]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[gameKey] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *CachedGamesManager) GetGame(ctx context.Context, gameID string) (map[string]string, error) {
+	gameKey := fmt.Sprintf("game:%s", gameID)
+	game, generation := c.get(gameKey)
+	if game != nil {
+		return game, nil
+	}
+
+	game, err := c.GamesManager.GetGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	c.put(gameKey, game, generation)
+	return game, nil
+}
+
+func (c *CachedGamesManager) GetArchivedGame(ctx context.Context, gameID string) (map[string]string, error) {
+	gameKey := fmt.Sprintf("archive:game:%s", gameID)
+	game, generation := c.get(gameKey)
+	if game != nil {
+		return game, nil
+	}
+
+	game, err := c.GamesManager.GetArchivedGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	c.put(gameKey, game, generation)
+	return game, nil
+}
+
+// GetGames serves what it can from the cache and fetches the rest in one round trip.
+func (c *CachedGamesManager) GetGames(ctx context.Context, gameIDs []string) ([]GameResult, error) {
+	return c.getGames(ctx, "game:", gameIDs, c.GamesManager.GetGames)
+}
+
+// GetArchivedGames is GetGames for archived games.
+func (c *CachedGamesManager) GetArchivedGames(ctx context.Context, gameIDs []string) ([]GameResult, error) {
+	return c.getGames(ctx, "archive:game:", gameIDs, c.GamesManager.GetArchivedGames)
+}
+
+// getGames serves the games cached under keyPrefix+gameID and gets the rest with fetch.
+func (c *CachedGamesManager) getGames(ctx context.Context, keyPrefix string, gameIDs []string, fetch func(context.Context, []string) ([]GameResult, error)) ([]GameResult, error) {
+	results := make([]GameResult, len(gameIDs))
+	var missing []string
+	var missingAt []int
+	var generation uint64
+	for i, gameID := range gameIDs {
+		results[i].GameID = gameID
+		var game map[string]string
+		game, generation = c.get(keyPrefix + gameID)
+		if game == nil {
+			missing = append(missing, gameID)
+			missingAt = append(missingAt, i)
+			continue
+		}
+		results[i].Data = game
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	fetched, err := fetch(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for j, result := range fetched {
+		results[missingAt[j]] = result
+		if result.Err == nil {
+			c.put(keyPrefix+result.GameID, result.Data, generation)
+		}
+	}
+	return results, nil
+}
+
+// CreateOrUpdateGame drops the game here right away, other replicas drop it once the
+// invalidation reaches them.
+func (c *CachedGamesManager) CreateOrUpdateGame(ctx context.Context, gameKey string, fields map[string]interface{}) error {
+	defer c.invalidate(gameKey)
+	return c.GamesManager.CreateOrUpdateGame(ctx, gameKey, fields)
+}
+
+func (c *CachedGamesManager) UpdateObservedGame(ctx context.Context, gameKey string, observed Observation, fields map[string]interface{}) error {
+	defer c.invalidate(gameKey)
+	return c.GamesManager.UpdateObservedGame(ctx, gameKey, observed, fields)
+}
+
+func (c *CachedGamesManager) ImportGame(ctx context.Context, gameKey string, observed Observation, fields map[string]interface{}) error {
+	defer c.invalidate(gameKey)
+	return c.GamesManager.ImportGame(ctx, gameKey, observed, fields)
+}
+
+func (c *CachedGamesManager) MarkObserved(ctx context.Context, gameKey, group, source string, observedAt time.Time) error {
+	defer c.invalidate(gameKey)
+	return c.GamesManager.MarkObserved(ctx, gameKey, group, source, observedAt)
+}
+
+func (c *CachedGamesManager) ArchivePastGames(ctx context.Context, teamID string, finishedBefore time.Time, ttl time.Duration) ([]string, error) {
+	archived, err := c.GamesManager.ArchivePastGames(ctx, teamID, finishedBefore, ttl)
+	for _, gameID := range archived {
+		c.invalidate(fmt.Sprintf("game:%s", gameID))
+	}
+	return archived, err
+}
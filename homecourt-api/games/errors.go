@@ -0,0 +1,25 @@
+package games
+
+import (
+	"errors"
+	"fmt"
+	"homecourt-api/teams"
+)
+
+// Errors returned by the GamesManager, wrapped around the underlying cause so both can be
+// checked with errors.Is.
+var (
+	ErrGameNotFound = errors.New("game not found")
+	ErrInvalidTeam  = errors.New("invalid team")
+	// ErrUnavailable means Redis couldn't be reached or failed the command.
+	ErrUnavailable = errors.New("game store unavailable")
+	// ErrOutOfOrder means a write was observed before what's stored and was skipped.
+	ErrOutOfOrder = errors.New("observed before the stored data")
+)
+
+func checkTeam(teamID string) error {
+	if _, ok := teams.Registry[teamID]; !ok {
+		return fmt.Errorf("%q: %w", teamID, ErrInvalidTeam)
+	}
+	return nil
+}
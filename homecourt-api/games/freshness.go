@@ -0,0 +1,147 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Field groups are the parts of a game that come from the same feed. Each is stamped with
+// when and where it was last observed, in <group>_observed_at (RFC3339) and <group>_source.
+const (
+	GroupTickets  = "tickets"
+	GroupOdds     = "odds"
+	GroupInjuries = "injuries"
+	GroupResults  = "results"
+	GroupSchedule = "schedule"
+)
+
+// Groups are all field groups, in the order they're reported in.
+var Groups = []string{GroupTickets, GroupOdds, GroupInjuries, GroupResults, GroupSchedule}
+
+// DefaultStaleAfter is how old each group may get before it's flagged stale. Results and the
+// schedule don't go stale, a final score is final and tip-offs are corrected by ticketmaster.
+var DefaultStaleAfter = map[string]time.Duration{
+	GroupTickets:  time.Hour,
+	GroupOdds:     30 * time.Minute,
+	GroupInjuries: 24 * time.Hour,
+}
+
+func ObservedAtField(group string) string { return group + "_observed_at" }
+func SourceField(group string) string     { return group + "_source" }
+func StaleField(group string) string      { return group + "_stale" }
+
+// Observation is when and from where a group of a game's fields was fetched.
+type Observation struct {
+	Group  string
+	Source string
+	At     time.Time
+}
+
+// UpdateObservedGame writes fields like CreateOrUpdateGame and stamps their group with
+// observed, in one step. If the group was already observed later the whole write is skipped
+// with ErrOutOfOrder, so an older message can't overwrite newer prices or odds.
+func (r *redisGamesManager) UpdateObservedGame(ctx context.Context, gameKey string, observed Observation, fields map[string]interface{}) error {
+	changed, err := r.setIfChanged(ctx, gameKey, &observed, false, fields)
+	if err != nil {
+		return fmt.Errorf("failed to update %s of game %s: %w", observed.Group, gameKey, err)
+	}
+	if gameID, ok := strings.CutPrefix(gameKey, "game:"); ok && changed && touchesIndex(fields) {
+		return r.IndexGame(ctx, gameID)
+	}
+	return nil
+}
+
+// ImportGame writes fields like UpdateObservedGame, but creates the game if it doesn't exist
+// yet. It's how homecourt-init imports the schedule. Once a feed has stamped the group (the
+// tickets feed corrects tip-offs), the import only fills in fields the game is missing.
+func (r *redisGamesManager) ImportGame(ctx context.Context, gameKey string, observed Observation, fields map[string]interface{}) error {
+	changed, err := r.setIfChanged(ctx, gameKey, &observed, true, fields)
+	if err != nil {
+		return fmt.Errorf("failed to import game %s: %w", gameKey, err)
+	}
+	if gameID, ok := strings.CutPrefix(gameKey, "game:"); ok && changed && touchesIndex(fields) {
+		return r.IndexGame(ctx, gameID)
+	}
+	return nil
+}
+
+// MarkObserved records that group of the game at gameKey was observed from source at
+// observedAt, unless it already has a later stamp. Games that don't exist (anymore) are
+// left alone. A new stamp is announced on InvalidationChannel.
+func (r *redisGamesManager) MarkObserved(ctx context.Context, gameKey, group, source string, observedAt time.Time) error {
+	observed := Observation{Group: group, Source: source, At: observedAt}
+	_, err := r.setIfChanged(ctx, gameKey, &observed, false, nil)
+	if errors.Is(err, ErrOutOfOrder) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark %s of %s observed: %w", group, gameKey, err)
+	}
+	return nil
+}
+
+// LastObserved is when any group of a game was last observed, zero when none was.
+func LastObserved(game map[string]string) time.Time {
+	var last time.Time
+	for _, group := range Groups {
+		observedAt, err := time.Parse(time.RFC3339, game[ObservedAtField(group)])
+		if err == nil && observedAt.After(last) {
+			last = observedAt
+		}
+	}
+	return last
+}
+
+// MarkStale adds <group>_stale, "true" or "false", to game for every group that has been
+// observed and has a threshold in staleAfter. Final games are left alone, the feeds stop
+// covering them and every group would only ever turn stale.
+func MarkStale(game map[string]string, staleAfter map[string]time.Duration, now time.Time) {
+	if game["status"] == "final" {
+		return
+	}
+	for _, group := range Groups {
+		threshold, ok := staleAfter[group]
+		if !ok {
+			continue
+		}
+		observedAt, err := time.Parse(time.RFC3339, game[ObservedAtField(group)])
+		if err != nil {
+			continue
+		}
+		stale := now.Sub(observedAt) > threshold
+		game[StaleField(group)] = fmt.Sprint(stale)
+	}
+}
+
+// ParseStaleAfter reads thresholds like "tickets=2h,odds=15m" on top of defaults. Groups that
+// aren't mentioned keep their default, 0 turns a group's check off.
+func ParseStaleAfter(s string, defaults map[string]time.Duration) (map[string]time.Duration, error) {
+	staleAfter := map[string]time.Duration{}
+	for group, threshold := range defaults {
+		staleAfter[group] = threshold
+	}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		group, value, ok := strings.Cut(pair, "=")
+		group = strings.TrimSpace(group)
+		if !ok || !slices.Contains(Groups, group) {
+			return nil, fmt.Errorf("invalid threshold %q, expected one of %s", pair, strings.Join(Groups, ", "))
+		}
+		threshold, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid threshold for %s: %q", group, value)
+		}
+		if threshold == 0 {
+			delete(staleAfter, group)
+			continue
+		}
+		staleAfter[group] = threshold
+	}
+	return staleAfter, nil
+}
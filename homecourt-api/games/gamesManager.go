@@ -3,6 +3,9 @@ package games
 import (
 	"context"
 	"fmt"
+	"homecourt-api/metrics"
+	"homecourt-api/tracing"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -22,16 +25,35 @@ type Game struct {
 	} `json:"injured_players"`
 }
 
+// GameResult is one game looked up by GetGames. Err is set instead of Data when that
+// game couldn't be fetched.
+type GameResult struct {
+	GameID string
+	Data   map[string]string
+	Err    error
+}
+
 // GamesManager defines the methods for managing  games and game details.
 type GamesManager interface {
 	//  games per team (ZSET of game IDs)
 
 	CreateOrUpdateGame(ctx context.Context, gameKey string, fields map[string]interface{}) error
+	UpdateObservedGame(ctx context.Context, gameKey string, observed Observation, fields map[string]interface{}) error
+	ImportGame(ctx context.Context, gameKey string, observed Observation, fields map[string]interface{}) error
 	AddUpcomingGame(ctx context.Context, zsetKey, gameID string, score int64) error
 	GetUpcomingGames(ctx context.Context, teamID string, count int64) ([]string, error)
 	GameExists(ctx context.Context, gameID string) (bool, error)
 	GetGame(ctx context.Context, gameID string) (map[string]string, error)
-	RemovePastGames(ctx context.Context, teamID string) error
+	GetGames(ctx context.Context, gameIDs []string) ([]GameResult, error)
+	ArchivePastGames(ctx context.Context, teamID string, finishedBefore time.Time, ttl time.Duration) ([]string, error)
+	GetArchivedGame(ctx context.Context, gameID string) (map[string]string, error)
+	GetArchivedGames(ctx context.Context, gameIDs []string) ([]GameResult, error)
+	GetPastGames(ctx context.Context, teamID string, count int64) ([]string, error)
+	IndexGame(ctx context.Context, gameID string) error
+	ReindexGames(ctx context.Context, teamID string) (int, error)
+	UnindexGames(ctx context.Context, gameIDs []string) error
+	FindGames(ctx context.Context, query GameQuery) ([]string, error)
+	MarkObserved(ctx context.Context, gameKey, group, source string, observedAt time.Time) error
 
 	// AddGame(ctx context.Context, teamID string, gameID string, startTime time.Time) error
 	// GetGames(ctx context.Context, teamID string, count int) ([]string, error)
@@ -44,6 +66,14 @@ type GamesManager interface {
 	// DeleteGame(ctx context.Context, gameID string) error
 }
 
+const (
+	// a game is considered finished this long after tip-off
+	GameLength = 4 * time.Hour
+
+	// how often ArchivePastGames tries to archive a game that keeps changing under it
+	archiveAttempts = 3
+)
+
 // redisGamesManager manages the Redis connection and operations.
 type redisGamesManager struct {
 	client *redis.Client
@@ -53,6 +83,8 @@ func NewGamesManager(addr string) (GamesManager, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr: addr,
 	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
 
 	ctx := context.Background()
 	_, err := client.Ping(ctx).Result()
@@ -63,10 +95,17 @@ func NewGamesManager(addr string) (GamesManager, error) {
 	return &redisGamesManager{client: client}, nil
 }
 
+// CreateOrUpdateGame writes fields to the hash at gameKey. Writes that change something bump
+// the game's version and are announced on InvalidationChannel, writes that don't are no-ops,
+// and so are writes to a game that doesn't exist (anymore). The correlation ID of ctx, if
+// any, is recorded with the change.
 func (r *redisGamesManager) CreateOrUpdateGame(ctx context.Context, gameKey string, fields map[string]interface{}) error {
-	err := r.client.HSet(ctx, gameKey, fields).Err()
+	changed, err := r.setIfChanged(ctx, gameKey, nil, false, fields)
 	if err != nil {
-		return fmt.Errorf("failed to create or update game %s: %v", gameKey, err)
+		return fmt.Errorf("failed to create or update game %s: %w", gameKey, err)
+	}
+	if gameID, ok := strings.CutPrefix(gameKey, "game:"); ok && changed && touchesIndex(fields) {
+		return r.IndexGame(ctx, gameID)
 	}
 	return nil
 }
@@ -76,7 +115,7 @@ func (r *redisGamesManager) GameExists(ctx context.Context, gameKey string) (boo
 	// Check if the key exists in Redis
 	exists, err := r.client.Exists(ctx, gameKey).Result()
 	if err != nil {
-		return false, fmt.Errorf("failed to check game existence: %v", err)
+		return false, fmt.Errorf("failed to check game existence: %w: %w", ErrUnavailable, err)
 	}
 
 	//127.0.0.1:6379> HGETALL "game:DAL NYK 11.28.2024"
@@ -102,25 +141,27 @@ func (r *redisGamesManager) AddUpcomingGame(ctx context.Context, zsetKey, gameID
 		Member: gameID,
 	}).Err()
 	if err != nil {
-		return fmt.Errorf("failed to add game to home games: %v", err)
+		return fmt.Errorf("failed to add game to home games: %w: %w", ErrUnavailable, err)
 	}
 	return nil
 }
 
 func (r *redisGamesManager) GetUpcomingGames(ctx context.Context, teamID string, count int64) ([]string, error) {
+	if err := checkTeam(teamID); err != nil {
+		return nil, err
+	}
 	zsetKey := fmt.Sprintf("team:%s:upcoming_home_games", teamID)
-	now := time.Now()
 
-	// Subtract 24 hours to get yesterday
-	yesterday := now.Add(-24 * time.Hour).Unix()
+	// games that tipped off less than GameLength ago are still being played, anything older
+	// is finished even if the janitor hasn't archived it yet
 	gameIDs, err := r.client.ZRangeByScore(ctx, zsetKey, &redis.ZRangeBy{
-		Min:    fmt.Sprintf("%d", yesterday),
+		Min:    fmt.Sprintf("%d", time.Now().Add(-GameLength).Unix()),
 		Max:    "+inf",
 		Offset: 0,
 		Count:  count,
 	}).Result()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get  games: %v", err)
+		return nil, fmt.Errorf("failed to get upcoming games: %w: %w", ErrUnavailable, err)
 	}
 	return gameIDs, nil
 }
@@ -129,20 +170,147 @@ func (r *redisGamesManager) GetGame(ctx context.Context, gameID string) (map[str
 	gameKey := fmt.Sprintf("game:%s", gameID)
 	gameData, err := r.client.HGetAll(ctx, gameKey).Result()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get game data: %v", err)
+		return nil, fmt.Errorf("failed to get game data: %w: %w", ErrUnavailable, err)
+	}
+	if len(gameData) == 0 {
+		return nil, fmt.Errorf("game %s: %w", gameID, ErrGameNotFound)
 	}
 	return gameData, nil
 }
 
-func (r *redisGamesManager) RemovePastGames(ctx context.Context, teamID string) error {
-	zsetKey := fmt.Sprintf("team:%s:_home_games", teamID)
-	now := time.Now().Unix()
-	// Remove games with scores less than current time
-	err := r.client.ZRemRangeByScore(ctx, zsetKey, "0", fmt.Sprintf("(%d", now)).Err()
+// GetGames fetches several games in one round trip. Results are in the order of gameIDs.
+// A game that doesn't exist or can't be read gets a per-item error; the returned error is
+// only set when the lookup as a whole failed, e.g. because ctx expired.
+func (r *redisGamesManager) GetGames(ctx context.Context, gameIDs []string) ([]GameResult, error) {
+	return r.getHashes(ctx, "game:", "game", gameIDs)
+}
+
+// GetArchivedGames is GetGames for archived games.
+func (r *redisGamesManager) GetArchivedGames(ctx context.Context, gameIDs []string) ([]GameResult, error) {
+	return r.getHashes(ctx, "archive:game:", "archived game", gameIDs)
+}
+
+// getHashes reads the hashes at keyPrefix+gameID in one pipeline. kind names the games in
+// errors.
+func (r *redisGamesManager) getHashes(ctx context.Context, keyPrefix, kind string, gameIDs []string) ([]GameResult, error) {
+	cmds := make([]*redis.MapStringStringCmd, len(gameIDs))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, gameID := range gameIDs {
+			cmds[i] = pipe.HGetAll(ctx, keyPrefix+gameID)
+		}
+		return nil
+	})
+	// a failed command fails Exec too, those are reported per item below
+	if err != nil && ctx.Err() != nil {
+		return nil, fmt.Errorf("failed to get %ss: %w: %w", kind, ErrUnavailable, ctx.Err())
+	}
+
+	results := make([]GameResult, len(gameIDs))
+	for i, cmd := range cmds {
+		results[i].GameID = gameIDs[i]
+		gameData, err := cmd.Result()
+		switch {
+		case err != nil:
+			results[i].Err = fmt.Errorf("failed to get %s data: %w: %w", kind, ErrUnavailable, err)
+		case len(gameData) == 0:
+			results[i].Err = fmt.Errorf("%s %s: %w", kind, gameIDs[i], ErrGameNotFound)
+		default:
+			results[i].Data = gameData
+		}
+	}
+	return results, nil
+}
+
+// ArchivePastGames moves every home game of teamID that tipped off before finishedBefore out of
+// the upcoming index and renames its hash to archive:game:<id>, so the final odds and ticket
+// prices are kept. Archived games are indexed for both teams under team:<id>:archived_games.
+// Archived hashes and the archive indexes expire after ttl.
+func (r *redisGamesManager) ArchivePastGames(ctx context.Context, teamID string, finishedBefore time.Time, ttl time.Duration) ([]string, error) {
+	zsetKey := fmt.Sprintf("team:%s:upcoming_home_games", teamID)
+
+	finished, err := r.client.ZRangeByScoreWithScores(ctx, zsetKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("(%d", finishedBefore.Unix()),
+	}).Result()
 	if err != nil {
-		return fmt.Errorf("failed to remove past games: %v", err)
+		return nil, fmt.Errorf("failed to get past games for %s: %w: %w", teamID, ErrUnavailable, err)
 	}
-	return nil
+
+	var archived []string
+	for _, z := range finished {
+		gameID := z.Member.(string)
+		gameKey := fmt.Sprintf("game:%s", gameID)
+
+		// the hash is watched from the read to the rename, an update in between aborts the
+		// transaction and the game is tried again
+		archiveGame := func(tx *redis.Tx) error {
+			// the index can outlive the hash (e.g. a ticket message for a game that was flushed)
+			awayTeam, err := tx.HGet(ctx, gameKey, "away_team").Result()
+			if err != nil && err != redis.Nil {
+				return err
+			}
+			exists := err == nil
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.ZRem(ctx, zsetKey, gameID)
+				unindexGame(ctx, pipe, gameID, teamID, awayTeam)
+				if !exists {
+					return nil
+				}
+
+				archivedGameKey := fmt.Sprintf("archive:game:%s", gameID)
+				pipe.Rename(ctx, gameKey, archivedGameKey)
+				pipe.Publish(ctx, InvalidationChannel, gameKey)
+				pipe.Expire(ctx, archivedGameKey, ttl)
+				for _, team := range []string{teamID, awayTeam} {
+					archiveKey := fmt.Sprintf("team:%s:archived_games", team)
+					pipe.ZAdd(ctx, archiveKey, redis.Z{Score: z.Score, Member: gameID})
+					pipe.Expire(ctx, archiveKey, ttl)
+				}
+				return nil
+			})
+			return err
+		}
+
+		err := r.client.Watch(ctx, archiveGame, gameKey)
+		for attempt := 1; err == redis.TxFailedErr && attempt < archiveAttempts; attempt++ {
+			err = r.client.Watch(ctx, archiveGame, gameKey)
+		}
+		if err == redis.TxFailedErr {
+			// still being written to, the next sweep picks it up
+			continue
+		}
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive game %s: %w: %w", gameID, ErrUnavailable, err)
+		}
+		archived = append(archived, gameID)
+	}
+	return archived, nil
+}
+
+func (r *redisGamesManager) GetArchivedGame(ctx context.Context, gameID string) (map[string]string, error) {
+	gameKey := fmt.Sprintf("archive:game:%s", gameID)
+	gameData, err := r.client.HGetAll(ctx, gameKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived game data: %w: %w", ErrUnavailable, err)
+	}
+	if len(gameData) == 0 {
+		return nil, fmt.Errorf("archived game %s: %w", gameID, ErrGameNotFound)
+	}
+	return gameData, nil
+}
+
+// GetPastGames returns the IDs of a team's most recent archived games, home or away, newest first.
+func (r *redisGamesManager) GetPastGames(ctx context.Context, teamID string, count int64) ([]string, error) {
+	if err := checkTeam(teamID); err != nil {
+		return nil, err
+	}
+	zsetKey := fmt.Sprintf("team:%s:archived_games", teamID)
+	gameIDs, err := r.client.ZRevRange(ctx, zsetKey, 0, count-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get past games: %w: %w", ErrUnavailable, err)
+	}
+	return gameIDs, nil
 }
 
 // func (r *redisGamesManager) RemovePastGames(ctx context.Context, teamID string) error {
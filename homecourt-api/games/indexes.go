@@ -0,0 +1,235 @@
+package games
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Secondary indexes over upcoming games, kept up to date by CreateOrUpdateGame and
+// ReindexGames and cleaned up by ArchivePastGames:
+//
+//	games:by_time               every game, scored by tip-off
+//	team:<abbr>:games           a team's home and away games, scored by tip-off
+//	games:by_price:<yyyy-mm-dd> games with ticket prices on a date, scored by lowest price
+const byTimeKey = "games:by_time"
+
+// the fields an index is built from, updating anything else doesn't touch the indexes
+var indexedFields = []string{"start_time", "home_team", "away_team", "lowest_ticket_price"}
+
+func teamGamesKey(teamID string) string {
+	return fmt.Sprintf("team:%s:games", teamID)
+}
+
+func byPriceKey(date time.Time) string {
+	return fmt.Sprintf("games:by_price:%s", date.Format("2006-01-02"))
+}
+
+// gameDate is the UTC date a game was scheduled on, which is the last part of its ID
+// ("DAL NYK 11.28.2024"). Unlike start_time it never changes, so a rescheduled game
+// can't leave a stale entry in an old date's price index.
+func gameDate(gameID string) (time.Time, error) {
+	parts := strings.Fields(gameID)
+	if len(parts) == 0 {
+		return time.Time{}, fmt.Errorf("invalid game id: %q", gameID)
+	}
+	return time.Parse("01.02.2006", parts[len(parts)-1])
+}
+
+func touchesIndex(fields map[string]interface{}) bool {
+	for _, field := range indexedFields {
+		if _, ok := fields[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IndexGame adds a game to the secondary indexes from what's in its hash.
+func (r *redisGamesManager) IndexGame(ctx context.Context, gameID string) error {
+	values, err := r.client.HMGet(ctx, fmt.Sprintf("game:%s", gameID), indexedFields...).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get game data: %w: %w", ErrUnavailable, err)
+	}
+	field := func(i int) string {
+		value, _ := values[i].(string)
+		return value
+	}
+	startTimeStr, homeTeam, awayTeam, price := field(0), field(1), field(2), field(3)
+
+	startTime, err := time.Parse(time.RFC3339, startTimeStr)
+	if err != nil {
+		// nothing to order it by yet
+		return nil
+	}
+	date, err := gameDate(gameID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		byTime := redis.Z{Score: float64(startTime.Unix()), Member: gameID}
+		pipe.ZAdd(ctx, byTimeKey, byTime)
+		for _, team := range []string{homeTeam, awayTeam} {
+			if team != "" {
+				pipe.ZAdd(ctx, teamGamesKey(team), byTime)
+			}
+		}
+		if lowestPrice, err := ParsePrice(price); err == nil {
+			pipe.ZAdd(ctx, byPriceKey(date), redis.Z{Score: lowestPrice, Member: gameID})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index game %s: %w: %w", gameID, ErrUnavailable, err)
+	}
+	return nil
+}
+
+// unindexGame removes a game from the secondary indexes.
+func unindexGame(ctx context.Context, pipe redis.Pipeliner, gameID, homeTeam, awayTeam string) {
+	pipe.ZRem(ctx, byTimeKey, gameID)
+	for _, team := range []string{homeTeam, awayTeam} {
+		if team != "" {
+			pipe.ZRem(ctx, teamGamesKey(team), gameID)
+		}
+	}
+	if date, err := gameDate(gameID); err == nil {
+		pipe.ZRem(ctx, byPriceKey(date), gameID)
+	}
+}
+
+// UnindexGames removes games that are gone from the secondary indexes. Their teams are
+// taken from the game IDs, which start with the home and away team.
+func (r *redisGamesManager) UnindexGames(ctx context.Context, gameIDs []string) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, gameID := range gameIDs {
+			var homeTeam, awayTeam string
+			if parts := strings.Fields(gameID); len(parts) == 3 {
+				homeTeam, awayTeam = parts[0], parts[1]
+			}
+			unindexGame(ctx, pipe, gameID, homeTeam, awayTeam)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unindex games: %w: %w", ErrUnavailable, err)
+	}
+	return nil
+}
+
+// ReindexGames indexes every upcoming home game of teamID. homecourt-init runs it after
+// importing the schedule, the janitor every sweep to repair indexes a failed write left behind.
+func (r *redisGamesManager) ReindexGames(ctx context.Context, teamID string) (int, error) {
+	gameIDs, err := r.client.ZRange(ctx, fmt.Sprintf("team:%s:upcoming_home_games", teamID), 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get upcoming games for %s: %w: %w", teamID, ErrUnavailable, err)
+	}
+	for i, gameID := range gameIDs {
+		err := r.IndexGame(ctx, gameID)
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(gameIDs), nil
+}
+
+// maxPriceIndexDays caps how many per-date price indexes FindGames unions before it
+// falls back to the time index
+const maxPriceIndexDays = 31
+
+// FindGames returns the IDs of the games that can match query, using the narrowest index
+// available. From, To, MaxPrice and the cursor are applied to the index, so is the page
+// size for tip-off ordered queries Search reads in batches. The rest is left to
+// GameQuery.Matches, the result is a superset the caller still has to filter.
+func (r *redisGamesManager) FindGames(ctx context.Context, query GameQuery) ([]string, error) {
+	if query.Team != "" {
+		if err := checkTeam(query.Team); err != nil {
+			return nil, err
+		}
+	}
+	after, err := decodeCursor(query.Cursor)
+	hasCursor := query.Cursor != "" && err == nil
+
+	// cheapest-first queries over a short range only need the priced games of those days
+	if query.Sort == SortPrice && !query.To.IsZero() && query.To.Sub(query.From) <= maxPriceIndexDays*24*time.Hour {
+		min, max := math.Inf(-1), math.Inf(1)
+		if query.MaxPrice > 0 {
+			max = query.MaxPrice
+		}
+		// games tied with the cursor are still read, Page sorts them out by ID
+		if hasCursor && query.Desc {
+			max = math.Min(max, after.Value)
+		} else if hasCursor {
+			min = after.Value
+		}
+		byPrice := &redis.ZRangeBy{Min: scoreBound(min), Max: scoreBound(max)}
+
+		var cmds []*redis.StringSliceCmd
+		_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			// game IDs carry the UTC date, go a day either side to catch late tip-offs
+			from := query.From.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+			for day := from; !day.After(query.To.UTC().AddDate(0, 0, 1)); day = day.AddDate(0, 0, 1) {
+				cmds = append(cmds, pipe.ZRangeByScore(ctx, byPriceKey(day), byPrice))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get games by price: %w: %w", ErrUnavailable, err)
+		}
+
+		var gameIDs []string
+		for _, cmd := range cmds {
+			gameIDs = append(gameIDs, cmd.Val()...)
+		}
+		return gameIDs, nil
+	}
+
+	key := byTimeKey
+	if query.Team != "" {
+		key = teamGamesKey(query.Team)
+	}
+	min, max := float64(query.From.Unix()), math.Inf(1)
+	if !query.To.IsZero() {
+		max = float64(query.To.Unix())
+	}
+	if query.indexOrdered() && hasCursor && query.Desc {
+		max = math.Min(max, after.Value)
+	} else if query.indexOrdered() && hasCursor {
+		min = math.Max(min, after.Value)
+	}
+	byTime := &redis.ZRangeBy{Min: scoreBound(min), Max: scoreBound(max)}
+	if count := query.count; count > 0 {
+		byTime.Offset, byTime.Count = query.offset, count
+	} else if query.indexOrdered() && query.Limit > 0 {
+		// callers that don't go through Search get their page and one more
+		byTime.Count = int64(query.Limit) + 1
+	}
+
+	var gameIDs []string
+	if query.indexOrdered() && query.Desc {
+		gameIDs, err = r.client.ZRevRangeByScore(ctx, key, byTime).Result()
+	} else {
+		gameIDs, err = r.client.ZRangeByScore(ctx, key, byTime).Result()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get games by time: %w: %w", ErrUnavailable, err)
+	}
+	return gameIDs, nil
+}
+
+// scoreBound formats a score for ZRANGEBYSCORE, infinities included.
+func scoreBound(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "+inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
@@ -0,0 +1,29 @@
+package games
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ImpliedProbability converts American moneyline odds like "-190" or "+155" into the
+// win probability they imply, ignoring the bookmaker's margin.
+func ImpliedProbability(odds string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(odds), 64)
+	if err != nil || (value > -100 && value < 100) {
+		return 0, fmt.Errorf("invalid moneyline odds: %q", odds)
+	}
+	if value < 0 {
+		return -value / (-value + 100), nil
+	}
+	return 100 / (value + 100), nil
+}
+
+// ParsePrice converts a stored ticket price like "$99.00" into a number.
+func ParsePrice(price string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(price), "$"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ticket price: %q", price)
+	}
+	return value, nil
+}
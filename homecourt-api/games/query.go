@@ -0,0 +1,269 @@
+package games
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SortTipOff = "tip_off"
+	SortPrice  = "price"
+	// SortOdds orders by the home team's implied win probability, biggest underdogs first
+	SortOdds = "odds"
+
+	LineFavorite = "favorite"
+	LineUnderdog = "underdog"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// GameQuery is a search over upcoming games. Zero values mean "don't filter".
+type GameQuery struct {
+	From        time.Time
+	To          time.Time
+	Team        string
+	Venue       string
+	MaxPrice    float64
+	HomeLine    string // LineFavorite or LineUnderdog, judged by home_team_odds
+	HasInjuries *bool
+	Sort        string
+	Desc        bool
+	Limit       int
+	Cursor      string
+
+	// where Search is in the index: FindGames skips offset entries and reads count, or
+	// every candidate when count is 0
+	offset int64
+	count  int64
+}
+
+// maxSearchBatch caps how many index entries Search reads in one round trip
+const maxSearchBatch = 1000
+
+// indexOrdered reports whether the query is sorted by what the indexes are scored by, so
+// FindGames can read them a page at a time.
+func (q GameQuery) indexOrdered() bool {
+	return q.Sort == "" || q.Sort == SortTipOff
+}
+
+// Matches reports whether a game passes every filter in the query. Games without the
+// field being sorted on never match, there's nothing to order them by.
+func (q GameQuery) Matches(game map[string]string) bool {
+	startTime, err := time.Parse(time.RFC3339, game["start_time"])
+	if err != nil || startTime.Before(q.From) || (!q.To.IsZero() && startTime.After(q.To)) {
+		return false
+	}
+	if q.Team != "" && game["home_team"] != q.Team && game["away_team"] != q.Team {
+		return false
+	}
+	if q.Venue != "" && !strings.Contains(strings.ToLower(game["venueName"]), strings.ToLower(q.Venue)) {
+		return false
+	}
+
+	price, priceErr := ParsePrice(game["lowest_ticket_price"])
+	if q.MaxPrice > 0 && (priceErr != nil || price > q.MaxPrice) {
+		return false
+	}
+	if q.Sort == SortPrice && priceErr != nil {
+		return false
+	}
+
+	probability, oddsErr := ImpliedProbability(game["home_team_odds"])
+	if q.HomeLine != "" && oddsErr != nil {
+		return false
+	}
+	if q.HomeLine == LineFavorite && probability <= 0.5 {
+		return false
+	}
+	if q.HomeLine == LineUnderdog && probability >= 0.5 {
+		return false
+	}
+	if q.Sort == SortOdds && oddsErr != nil {
+		return false
+	}
+
+	if q.HasInjuries != nil {
+		injuries := game["injured_players"]
+		hasInjuries := injuries != "" && injuries != "[]"
+		if hasInjuries != *q.HasInjuries {
+			return false
+		}
+	}
+	return true
+}
+
+// sortValue is the number a game is ordered by for the query's sort.
+func (q GameQuery) sortValue(game map[string]string) float64 {
+	switch q.Sort {
+	case SortPrice:
+		price, _ := ParsePrice(game["lowest_ticket_price"])
+		return price
+	case SortOdds:
+		probability, _ := ImpliedProbability(game["home_team_odds"])
+		return probability
+	default:
+		startTime, _ := time.Parse(time.RFC3339, game["start_time"])
+		return float64(startTime.Unix())
+	}
+}
+
+// cursor marks the last game of a page. Pages are keyed on (sort value, game ID) rather
+// than an offset so games coming and going between requests don't shift later pages.
+// Ties are broken by ID in the same direction as the sort, like Redis orders them.
+type cursor struct {
+	Value  float64 `json:"v"`
+	GameID string  `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Page sorts games that already passed Matches and returns the page after q.Cursor,
+// along with the cursor for the page after that ("" on the last page). Each game must
+// carry its ID under "game_id".
+func (q GameQuery) Page(games []map[string]string) ([]map[string]string, string, error) {
+	less := func(a, b cursor) bool {
+		if a.Value != b.Value {
+			return (a.Value < b.Value) != q.Desc
+		}
+		if q.Desc {
+			return a.GameID > b.GameID
+		}
+		return a.GameID < b.GameID
+	}
+	key := func(game map[string]string) cursor {
+		return cursor{Value: q.sortValue(game), GameID: game["game_id"]}
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		return less(key(games[i]), key(games[j]))
+	})
+
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		start := sort.Search(len(games), func(i int) bool {
+			return less(after, key(games[i]))
+		})
+		games = games[start:]
+	}
+
+	if q.Limit <= 0 || len(games) <= q.Limit {
+		return games, "", nil
+	}
+	games = games[:q.Limit]
+	return games, encodeCursor(key(games[len(games)-1])), nil
+}
+
+// Validate checks the options that can't be caught while parsing them.
+func (q GameQuery) Validate() error {
+	switch q.Sort {
+	case "", SortTipOff, SortPrice, SortOdds:
+	default:
+		return fmt.Errorf("sort must be one of %s, %s, %s", SortTipOff, SortPrice, SortOdds)
+	}
+	switch q.HomeLine {
+	case "", LineFavorite, LineUnderdog:
+	default:
+		return fmt.Errorf("home must be %s or %s", LineFavorite, LineUnderdog)
+	}
+	if q.MaxPrice < 0 {
+		return fmt.Errorf("max_price must not be negative")
+	}
+	if !q.To.IsZero() && q.To.Before(q.From) {
+		return fmt.Errorf("to must not be before from")
+	}
+	if q.HasInjuries != nil && *q.HasInjuries {
+		// nothing stores injury reports yet, so no game would ever match
+		return fmt.Errorf("has_injuries=true isn't supported yet")
+	}
+	if q.Cursor != "" {
+		if _, err := decodeCursor(q.Cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Search runs query against m and returns the page of matching games after its cursor,
+// with the cursor of the next page ("" on the last one). Candidates come from FindGames
+// and are post-filtered with Matches for what the indexes can't tell. Tip-off ordered
+// queries read the index in growing batches until the page is full, others read every
+// candidate in range at once. Games that couldn't be read are returned in failed instead
+// of failing the search, games that are gone (the indexes can briefly outlive an archived
+// game) are skipped and pruned from the indexes.
+func Search(ctx context.Context, m GamesManager, query GameQuery) (page []map[string]string, nextCursor string, failed []GameResult, err error) {
+	batch := query
+	if query.indexOrdered() && query.Limit > 0 {
+		// one more than the page, to know whether there's a next one
+		batch.count = int64(query.Limit) + 1
+	}
+
+	matched := []map[string]string{}
+	for {
+		gameIDs, err := m.FindGames(ctx, batch)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		results, err := m.GetGames(ctx, gameIDs)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		var gone []string
+		for _, result := range results {
+			if errors.Is(result.Err, ErrGameNotFound) {
+				gone = append(gone, result.GameID)
+				continue
+			}
+			if result.Err != nil {
+				failed = append(failed, result)
+				continue
+			}
+			if !query.Matches(result.Data) {
+				continue
+			}
+			result.Data["game_id"] = result.GameID
+			matched = append(matched, result.Data)
+		}
+		if len(gone) > 0 {
+			if err := m.UnindexGames(ctx, gone); err != nil {
+				// the next search tries again
+				slog.Warn("failed to prune games from the indexes", "games", gone, "err", err)
+			}
+		}
+
+		page, nextCursor, err = query.Page(matched)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if batch.count == 0 || int64(len(gameIDs)) < batch.count || nextCursor != "" {
+			return page, nextCursor, failed, nil
+		}
+		// the filters are throwing most of the index away, read more of it at a time
+		batch.offset += int64(len(gameIDs))
+		batch.count = min(2*batch.count, maxSearchBatch)
+	}
+}
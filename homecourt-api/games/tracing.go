@@ -0,0 +1,162 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"homecourt-api/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("homecourt-api/games")
+
+// TracedGamesManager adds a span for every call to another GamesManager. The Redis commands
+// the call sends show up under it.
+type TracedGamesManager struct {
+	GamesManager
+}
+
+func NewTracedGamesManager(manager GamesManager) *TracedGamesManager {
+	return &TracedGamesManager{GamesManager: manager}
+}
+
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "GamesManager."+method, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, a game that doesn't exist is an answer rather than a failure.
+func endSpan(span trace.Span, err error) {
+	if errors.Is(err, ErrGameNotFound) {
+		span.SetAttributes(attribute.Bool("game.found", false))
+		err = nil
+	}
+	if errors.Is(err, ErrOutOfOrder) {
+		span.SetAttributes(attribute.Bool("game.out_of_order", true))
+		err = nil
+	}
+	tracing.End(span, err)
+}
+
+func (t *TracedGamesManager) CreateOrUpdateGame(ctx context.Context, gameKey string, fields map[string]interface{}) error {
+	ctx, span := startSpan(ctx, "CreateOrUpdateGame", attribute.String("game.key", gameKey), attribute.Int("game.fields", len(fields)))
+	err := t.GamesManager.CreateOrUpdateGame(ctx, gameKey, fields)
+	endSpan(span, err)
+	return err
+}
+
+func (t *TracedGamesManager) UpdateObservedGame(ctx context.Context, gameKey string, observed Observation, fields map[string]interface{}) error {
+	ctx, span := startSpan(ctx, "UpdateObservedGame", attribute.String("game.key", gameKey), attribute.String("group", observed.Group), attribute.String("source", observed.Source), attribute.Int("game.fields", len(fields)))
+	err := t.GamesManager.UpdateObservedGame(ctx, gameKey, observed, fields)
+	endSpan(span, err)
+	return err
+}
+
+func (t *TracedGamesManager) ImportGame(ctx context.Context, gameKey string, observed Observation, fields map[string]interface{}) error {
+	ctx, span := startSpan(ctx, "ImportGame", attribute.String("game.key", gameKey), attribute.String("group", observed.Group), attribute.String("source", observed.Source), attribute.Int("game.fields", len(fields)))
+	err := t.GamesManager.ImportGame(ctx, gameKey, observed, fields)
+	endSpan(span, err)
+	return err
+}
+
+func (t *TracedGamesManager) AddUpcomingGame(ctx context.Context, zsetKey, gameID string, score int64) error {
+	ctx, span := startSpan(ctx, "AddUpcomingGame", attribute.String("game.id", gameID))
+	err := t.GamesManager.AddUpcomingGame(ctx, zsetKey, gameID, score)
+	endSpan(span, err)
+	return err
+}
+
+func (t *TracedGamesManager) GetUpcomingGames(ctx context.Context, teamID string, count int64) ([]string, error) {
+	ctx, span := startSpan(ctx, "GetUpcomingGames", attribute.String("team", teamID))
+	gameIDs, err := t.GamesManager.GetUpcomingGames(ctx, teamID, count)
+	endSpan(span, err)
+	return gameIDs, err
+}
+
+func (t *TracedGamesManager) GameExists(ctx context.Context, gameKey string) (bool, error) {
+	ctx, span := startSpan(ctx, "GameExists", attribute.String("game.key", gameKey))
+	exists, err := t.GamesManager.GameExists(ctx, gameKey)
+	endSpan(span, err)
+	return exists, err
+}
+
+func (t *TracedGamesManager) GetGame(ctx context.Context, gameID string) (map[string]string, error) {
+	ctx, span := startSpan(ctx, "GetGame", attribute.String("game.id", gameID))
+	game, err := t.GamesManager.GetGame(ctx, gameID)
+	endSpan(span, err)
+	return game, err
+}
+
+func (t *TracedGamesManager) GetGames(ctx context.Context, gameIDs []string) ([]GameResult, error) {
+	ctx, span := startSpan(ctx, "GetGames", attribute.Int("game.count", len(gameIDs)))
+	results, err := t.GamesManager.GetGames(ctx, gameIDs)
+	endSpan(span, err)
+	return results, err
+}
+
+func (t *TracedGamesManager) ArchivePastGames(ctx context.Context, teamID string, finishedBefore time.Time, ttl time.Duration) ([]string, error) {
+	ctx, span := startSpan(ctx, "ArchivePastGames", attribute.String("team", teamID))
+	archived, err := t.GamesManager.ArchivePastGames(ctx, teamID, finishedBefore, ttl)
+	span.SetAttributes(attribute.Int("game.archived", len(archived)))
+	endSpan(span, err)
+	return archived, err
+}
+
+func (t *TracedGamesManager) GetArchivedGame(ctx context.Context, gameID string) (map[string]string, error) {
+	ctx, span := startSpan(ctx, "GetArchivedGame", attribute.String("game.id", gameID))
+	game, err := t.GamesManager.GetArchivedGame(ctx, gameID)
+	endSpan(span, err)
+	return game, err
+}
+
+func (t *TracedGamesManager) GetArchivedGames(ctx context.Context, gameIDs []string) ([]GameResult, error) {
+	ctx, span := startSpan(ctx, "GetArchivedGames", attribute.Int("game.count", len(gameIDs)))
+	results, err := t.GamesManager.GetArchivedGames(ctx, gameIDs)
+	endSpan(span, err)
+	return results, err
+}
+
+func (t *TracedGamesManager) GetPastGames(ctx context.Context, teamID string, count int64) ([]string, error) {
+	ctx, span := startSpan(ctx, "GetPastGames", attribute.String("team", teamID))
+	gameIDs, err := t.GamesManager.GetPastGames(ctx, teamID, count)
+	endSpan(span, err)
+	return gameIDs, err
+}
+
+func (t *TracedGamesManager) IndexGame(ctx context.Context, gameID string) error {
+	ctx, span := startSpan(ctx, "IndexGame", attribute.String("game.id", gameID))
+	err := t.GamesManager.IndexGame(ctx, gameID)
+	endSpan(span, err)
+	return err
+}
+
+func (t *TracedGamesManager) ReindexGames(ctx context.Context, teamID string) (int, error) {
+	ctx, span := startSpan(ctx, "ReindexGames", attribute.String("team", teamID))
+	indexed, err := t.GamesManager.ReindexGames(ctx, teamID)
+	endSpan(span, err)
+	return indexed, err
+}
+
+func (t *TracedGamesManager) UnindexGames(ctx context.Context, gameIDs []string) error {
+	ctx, span := startSpan(ctx, "UnindexGames", attribute.Int("game.count", len(gameIDs)))
+	err := t.GamesManager.UnindexGames(ctx, gameIDs)
+	endSpan(span, err)
+	return err
+}
+
+func (t *TracedGamesManager) FindGames(ctx context.Context, query GameQuery) ([]string, error) {
+	ctx, span := startSpan(ctx, "FindGames", attribute.String("team", query.Team), attribute.String("sort", query.Sort))
+	gameIDs, err := t.GamesManager.FindGames(ctx, query)
+	span.SetAttributes(attribute.Int("game.count", len(gameIDs)))
+	endSpan(span, err)
+	return gameIDs, err
+}
+
+func (t *TracedGamesManager) MarkObserved(ctx context.Context, gameKey, group, source string, observedAt time.Time) error {
+	ctx, span := startSpan(ctx, "MarkObserved", attribute.String("game.key", gameKey), attribute.String("group", group), attribute.String("source", source))
+	err := t.GamesManager.MarkObserved(ctx, gameKey, group, source, observedAt)
+	endSpan(span, err)
+	return err
+}
@@ -0,0 +1,131 @@
+package games
+
+import (
+	"context"
+	"fmt"
+	"homecourt-api/correlation"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Every write through CreateOrUpdateGame that changes a game bumps these fields in its hash,
+// so clients and caches can tell whether a game changed without comparing it field by field.
+const (
+	VersionField   = "version"
+	UpdatedAtField = "updated_at" // RFC3339, when version was last bumped
+	// the correlation ID of the provider fetch behind the change, see the correlation package
+	CorrelationIDField = "correlation_id"
+)
+
+// InvalidationChannel gets the key of every game hash that changed or was archived.
+const InvalidationChannel = "homecourt:game_invalidations"
+
+// updateGame writes the field/value pairs after ARGV[8] to the hash only if one of them
+// differs from what's stored, then bumps the version. The producers resend unchanged data
+// every tick, which must not look like a new version.
+//
+// When ARGV[4] names a group's observed_at field, the write is also stamped with the
+// observation in ARGV[5..7], unless the stored stamp is later: messages can arrive out of
+// order, and then neither the fields nor the stamp are touched and -1 is returned. Stamps
+// aren't changes, they don't bump the version, but they are announced like one so caches
+// don't serve an old stamp. Missing games are left alone: the janitor may have just archived
+// the game, and a partial hash written here would later be renamed over the archived one.
+//
+// ARGV[8] is 'import' for the schedule import, which may create the game. Once another
+// source has stamped the group, the import only fills in fields the game doesn't have, so
+// it can't undo a feed's correction, and leaves the stamp alone.
+var updateGame = redis.NewScript(`
+local exists = redis.call('EXISTS', KEYS[1]) == 1
+local import = ARGV[8] == 'import'
+if not exists and not import then
+	return 0
+end
+local stamped = ARGV[4] ~= ''
+local yield = import and exists and stamped and (redis.call('HGET', KEYS[1], ARGV[6]) or ARGV[7]) ~= ARGV[7]
+if stamped and exists and not yield then
+	local observed = redis.call('HGET', KEYS[1], ARGV[4])
+	if observed and observed > ARGV[5] then
+		return -1
+	end
+end
+
+local updates = {}
+for i = 9, #ARGV, 2 do
+	local stored = redis.call('HGET', KEYS[1], ARGV[i])
+	if (yield and not stored) or (not yield and stored ~= ARGV[i + 1]) then
+		table.insert(updates, ARGV[i])
+		table.insert(updates, ARGV[i + 1])
+	end
+end
+
+local changed = #updates > 0
+local announce = changed
+if changed then
+	redis.call('HSET', KEYS[1], unpack(updates))
+	redis.call('HINCRBY', KEYS[1], 'version', 1)
+	redis.call('HSET', KEYS[1], 'updated_at', ARGV[1])
+	if ARGV[3] ~= '' then
+		redis.call('HSET', KEYS[1], 'correlation_id', ARGV[3])
+	else
+		redis.call('HDEL', KEYS[1], 'correlation_id')
+	end
+end
+if stamped and not yield and (redis.call('HGET', KEYS[1], ARGV[4]) ~= ARGV[5] or redis.call('HGET', KEYS[1], ARGV[6]) ~= ARGV[7]) then
+	redis.call('HSET', KEYS[1], ARGV[4], ARGV[5], ARGV[6], ARGV[7])
+	announce = true
+end
+if announce then
+	redis.call('PUBLISH', ARGV[2], KEYS[1])
+end
+if changed then
+	return 1
+end
+return 0
+`)
+
+// setIfChanged runs updateGame and reports whether the game changed. observed may be nil
+// for writes that aren't from a feed, importing is only set by ImportGame.
+func (r *redisGamesManager) setIfChanged(ctx context.Context, gameKey string, observed *Observation, importing bool, fields map[string]interface{}) (bool, error) {
+	args := make([]interface{}, 0, 8+2*len(fields))
+	args = append(args, time.Now().UTC().Format(time.RFC3339), InvalidationChannel, correlation.FromContext(ctx))
+	if observed != nil {
+		args = append(args,
+			ObservedAtField(observed.Group), observed.At.UTC().Format(time.RFC3339),
+			SourceField(observed.Group), observed.Source,
+		)
+	} else {
+		args = append(args, "", "", "", "")
+	}
+	if importing {
+		args = append(args, "import")
+	} else {
+		args = append(args, "")
+	}
+	for field, value := range fields {
+		// formatted the way HSET would store it, or the comparison never matches
+		args = append(args, field, fmt.Sprint(value))
+	}
+	changed, err := updateGame.Run(ctx, r.client, []string{gameKey}, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	if changed == -1 {
+		return false, fmt.Errorf("observed at %s: %w", observed.At.UTC().Format(time.RFC3339), ErrOutOfOrder)
+	}
+	return changed == 1, nil
+}
+
+// Version is a game's version, 0 for games that haven't been written through the
+// GamesManager yet (homecourt-init used to write the schedule straight to Redis).
+func Version(game map[string]string) int64 {
+	version, _ := strconv.ParseInt(game[VersionField], 10, 64)
+	return version
+}
+
+// UpdatedAt is when a game last changed, zero when that isn't known.
+func UpdatedAt(game map[string]string) time.Time {
+	updatedAt, _ := time.Parse(time.RFC3339, game[UpdatedAtField])
+	return updatedAt
+}
@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"homecourt-api/games"
+	"net/http"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+var Manager games.GamesManager
+
+//go:embed schema.graphql
+var Schema string
+
+const (
+	// how long a query may spend reading games from Redis
+	queryTimeout = 5 * time.Second
+
+	// games and teams link to each other, so without a cap on nesting one query could
+	// fan out into every game over and over. Deep enough for teams{upcomingGames{odds{favorite{name}}}}.
+	maxDepth = 5
+
+	// the most games a list field returns, like the gRPC API's page size
+	maxFirst = 100
+)
+
+// Handler serves GraphQL queries over the game data, as POSTed JSON
+// {"query": ..., "variables": ...}.
+func Handler() http.Handler {
+	schema := graphql.MustParseSchema(Schema, &resolver{}, graphql.MaxDepth(maxDepth))
+	relay := &relay.Handler{Schema: schema}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), queryTimeout)
+		defer cancel()
+
+		ctx = context.WithValue(ctx, loadersKey{}, &loaders{
+			games:    newGameLoader(ctx, Manager.GetGames),
+			archived: newGameLoader(ctx, Manager.GetArchivedGames),
+		})
+		relay.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"context"
+	"homecourt-api/games"
+	"sync"
+	"time"
+)
+
+const (
+	// how long a loader waits for more keys before fetching a batch
+	batchWait = time.Millisecond
+	// a batch is fetched straight away once it gets this big
+	maxBatch = 100
+)
+
+type fetchFunc func(ctx context.Context, gameIDs []string) ([]games.GameResult, error)
+
+type thunk struct {
+	done chan struct{}
+	data map[string]string
+	err  error
+}
+
+// gameLoader batches the game lookups made while resolving one query, so a query for
+// every team's upcoming games costs a handful of pipelined round trips instead of one per
+// game. Results are cached for the rest of the request.
+type gameLoader struct {
+	ctx   context.Context
+	fetch fetchFunc
+
+	mu      sync.Mutex
+	cache   map[string]*thunk
+	pending []string
+	timer   *time.Timer
+}
+
+func newGameLoader(ctx context.Context, fetch fetchFunc) *gameLoader {
+	return &gameLoader{ctx: ctx, fetch: fetch, cache: make(map[string]*thunk)}
+}
+
+// Load returns the hash of a game, empty if it doesn't exist.
+func (l *gameLoader) Load(gameID string) (map[string]string, error) {
+	t := l.enqueue(gameID)
+	select {
+	case <-t.done:
+		return t.data, t.err
+	case <-l.ctx.Done():
+		return nil, l.ctx.Err()
+	}
+}
+
+func (l *gameLoader) enqueue(gameID string) *thunk {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if t, ok := l.cache[gameID]; ok {
+		return t
+	}
+	t := &thunk{done: make(chan struct{})}
+	l.cache[gameID] = t
+	l.pending = append(l.pending, gameID)
+
+	switch {
+	case len(l.pending) >= maxBatch:
+		l.dispatchLocked()
+	case l.timer == nil:
+		l.timer = time.AfterFunc(batchWait, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.dispatchLocked()
+		})
+	}
+	return t
+}
+
+// dispatchLocked fetches the pending batch in the background. l.mu must be held.
+func (l *gameLoader) dispatchLocked() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if len(l.pending) == 0 {
+		return
+	}
+	batch := l.pending
+	l.pending = nil
+
+	thunks := make([]*thunk, len(batch))
+	for i, gameID := range batch {
+		thunks[i] = l.cache[gameID]
+	}
+
+	go func() {
+		results, err := l.fetch(l.ctx, batch)
+		for i, t := range thunks {
+			switch {
+			case err != nil:
+				t.err = err
+			case i < len(results):
+				t.data, t.err = results[i].Data, results[i].Err
+			}
+			close(t.done)
+		}
+	}()
+}
@@ -0,0 +1,271 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"homecourt-api/games"
+	"homecourt-api/teams"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type loadersKey struct{}
+
+// loaders are created per request, so nothing is cached between queries.
+type loaders struct {
+	games    *gameLoader
+	archived *gameLoader
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+type resolver struct{}
+
+func (*resolver) Teams() []*teamResolver {
+	abbreviations := make([]string, 0, len(teams.Registry))
+	for abbreviation := range teams.Registry {
+		abbreviations = append(abbreviations, abbreviation)
+	}
+	sort.Strings(abbreviations)
+
+	resolvers := make([]*teamResolver, len(abbreviations))
+	for i, abbreviation := range abbreviations {
+		resolvers[i] = &teamResolver{teams.Registry[abbreviation]}
+	}
+	return resolvers
+}
+
+func (*resolver) Team(args struct{ Abbreviation string }) *teamResolver {
+	return teamByAbbreviation(args.Abbreviation)
+}
+
+func (*resolver) Game(ctx context.Context, args struct{ ID graphql.ID }) (*gameResolver, error) {
+	gameID := string(args.ID)
+	data, err := loadersFrom(ctx).games.Load(gameID)
+	if err == nil {
+		return &gameResolver{id: gameID, data: data}, nil
+	}
+	if !errors.Is(err, games.ErrGameNotFound) {
+		return nil, err
+	}
+
+	// finished games are only in the archive
+	archived, err := loadersFrom(ctx).archived.Load(gameID)
+	if errors.Is(err, games.ErrGameNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gameResolver{id: gameID, data: archived}, nil
+}
+
+func teamByAbbreviation(abbreviation string) *teamResolver {
+	team, ok := teams.Registry[abbreviation]
+	if !ok {
+		return nil
+	}
+	return &teamResolver{team}
+}
+
+type teamResolver struct {
+	team teams.Team
+}
+
+func (t *teamResolver) Abbreviation() string { return t.team.Abbreviation }
+func (t *teamResolver) Name() string         { return t.team.Name }
+func (t *teamResolver) City() string         { return t.team.City }
+func (t *teamResolver) Timezone() string     { return t.team.Timezone }
+
+func (t *teamResolver) UpcomingGames(ctx context.Context, args struct{ First int32 }) ([]*gameResolver, error) {
+	gameIDs, err := Manager.GetUpcomingGames(ctx, t.team.Abbreviation, clampFirst(args.First))
+	if err != nil {
+		return nil, err
+	}
+	return loadGames(loadersFrom(ctx).games, gameIDs)
+}
+
+func (t *teamResolver) RecentResults(ctx context.Context, args struct{ First int32 }) ([]*gameResolver, error) {
+	gameIDs, err := Manager.GetPastGames(ctx, t.team.Abbreviation, clampFirst(args.First))
+	if err != nil {
+		return nil, err
+	}
+	return loadGames(loadersFrom(ctx).archived, gameIDs)
+}
+
+// clampFirst keeps first between 1 and maxFirst. Redis reads a count under 1 as "all of them".
+func clampFirst(first int32) int64 {
+	return int64(min(max(first, 1), maxFirst))
+}
+
+// loadGames loads gameIDs in one batch. Games that can't be read are left out of the list
+// rather than failing it.
+func loadGames(loader *gameLoader, gameIDs []string) ([]*gameResolver, error) {
+	thunks := make([]*thunk, len(gameIDs))
+	for i, gameID := range gameIDs {
+		thunks[i] = loader.enqueue(gameID)
+	}
+
+	resolvers := []*gameResolver{}
+	for i, t := range thunks {
+		select {
+		case <-t.done:
+		case <-loader.ctx.Done():
+			return nil, loader.ctx.Err()
+		}
+		// the archive index outlives the oldest archived hashes
+		if errors.Is(t.err, games.ErrGameNotFound) {
+			continue
+		}
+		if t.err != nil {
+			slog.ErrorContext(loader.ctx, "error fetching game", "game_id", gameIDs[i], "err", t.err)
+			continue
+		}
+		resolvers = append(resolvers, &gameResolver{id: gameIDs[i], data: t.data})
+	}
+	return resolvers, nil
+}
+
+type gameResolver struct {
+	id   string
+	data map[string]string
+}
+
+// optional returns nil for fields the game doesn't have yet.
+func (g *gameResolver) optional(field string) *string {
+	value, ok := g.data[field]
+	if !ok || value == "" {
+		return nil
+	}
+	return &value
+}
+
+func (g *gameResolver) optionalInt(field string) *int32 {
+	value, err := strconv.Atoi(g.data[field])
+	if err != nil {
+		return nil
+	}
+	n := int32(value)
+	return &n
+}
+
+func (g *gameResolver) ID() graphql.ID        { return graphql.ID(g.id) }
+func (g *gameResolver) Venue() *string        { return g.optional("venueName") }
+func (g *gameResolver) StartTime() string     { return g.data["start_time"] }
+func (g *gameResolver) Week() *int32          { return g.optionalInt("week") }
+func (g *gameResolver) Hashtag() *string      { return g.optional("hashtag") }
+func (g *gameResolver) Status() *string       { return g.optional("status") }
+func (g *gameResolver) HomeScore() *int32     { return g.optionalInt("home_score") }
+func (g *gameResolver) AwayScore() *int32     { return g.optionalInt("away_score") }
+func (g *gameResolver) Winner() *teamResolver { return teamByAbbreviation(g.data["winner"]) }
+
+// HomeTeam and AwayTeam fall back to a bare team for abbreviations outside the registry,
+// the fields are non-null.
+func (g *gameResolver) HomeTeam() *teamResolver { return g.team(g.data["home_team"]) }
+func (g *gameResolver) AwayTeam() *teamResolver { return g.team(g.data["away_team"]) }
+
+func (g *gameResolver) team(abbreviation string) *teamResolver {
+	if t := teamByAbbreviation(abbreviation); t != nil {
+		return t
+	}
+	return &teamResolver{teams.Team{Abbreviation: abbreviation, Name: abbreviation}}
+}
+
+func (g *gameResolver) TipOffLocal(args struct{ Tz *string }) (string, error) {
+	startTime, err := time.Parse(time.RFC3339, g.data["start_time"])
+	if err != nil {
+		return "", err
+	}
+	var loc *time.Location
+	if args.Tz != nil {
+		loc, err = time.LoadLocation(*args.Tz)
+	} else {
+		loc, err = teams.Location(g.data["home_team"])
+	}
+	if err != nil {
+		return "", err
+	}
+	return startTime.In(loc).Format(time.RFC3339), nil
+}
+
+func (g *gameResolver) Odds() *oddsResolver {
+	odds := g.data["home_team_odds"]
+	probability, err := games.ImpliedProbability(odds)
+	if err != nil {
+		return nil
+	}
+	return &oddsResolver{game: g, odds: odds, probability: probability}
+}
+
+func (g *gameResolver) Tickets() *ticketsResolver {
+	display := g.data["lowest_ticket_price"]
+	price, err := games.ParsePrice(display)
+	if err != nil {
+		return nil
+	}
+	return &ticketsResolver{price: price, display: display}
+}
+
+func (g *gameResolver) Injuries() ([]*injuryResolver, error) {
+	resolvers := []*injuryResolver{}
+	if g.data["injured_players"] == "" {
+		return resolvers, nil
+	}
+
+	var injured []struct {
+		Team       string `json:"team"`
+		PlayerName string `json:"player_name"`
+		Status     string `json:"status"`
+	}
+	err := json.Unmarshal([]byte(g.data["injured_players"]), &injured)
+	if err != nil {
+		return nil, err
+	}
+	for _, player := range injured {
+		resolvers = append(resolvers, &injuryResolver{team: player.Team, playerName: player.PlayerName, status: player.Status})
+	}
+	return resolvers, nil
+}
+
+type oddsResolver struct {
+	game        *gameResolver
+	odds        string
+	probability float64
+}
+
+func (o *oddsResolver) HomeTeamOdds() string        { return o.odds }
+func (o *oddsResolver) HomeWinProbability() float64 { return o.probability }
+func (o *oddsResolver) AwayWinProbability() float64 { return 1 - o.probability }
+func (o *oddsResolver) Result() *string             { return o.game.optional("home_team_odds_result") }
+
+func (o *oddsResolver) Favorite() *teamResolver {
+	if o.probability >= 0.5 {
+		return o.game.HomeTeam()
+	}
+	return o.game.AwayTeam()
+}
+
+type ticketsResolver struct {
+	price   float64
+	display string
+}
+
+func (t *ticketsResolver) LowestPrice() float64       { return t.price }
+func (t *ticketsResolver) LowestPriceDisplay() string { return t.display }
+
+type injuryResolver struct {
+	team       string
+	playerName string
+	status     string
+}
+
+func (i *injuryResolver) Team() *teamResolver { return teamByAbbreviation(i.team) }
+func (i *injuryResolver) PlayerName() string  { return i.playerName }
+func (i *injuryResolver) Status() string      { return i.status }
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"homecourt-api/alerts"
+	"homecourt-api/problem"
+	"homecourt-api/teams"
+	"net/http"
+)
+
+var Alerts *alerts.Evaluator
+
+type AlertsResponse struct {
+	Rules []alerts.Rule `json:"rules"`
+}
+
+// CreateAlertHandler serves POST /v1/alerts, registering a rule from the JSON body for the
+// signed-in user.
+func CreateAlertHandler(w http.ResponseWriter, r *http.Request) {
+	var rule alerts.Rule
+	err := json.NewDecoder(r.Body).Decode(&rule)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid request body")
+		return
+	}
+	rule.OwnerID = currentUser(r).ID
+
+	err = rule.Validate()
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, err.Error())
+		return
+	}
+	if _, ok := teams.Registry[rule.Team]; rule.Team != "" && !ok {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidTeam, fmt.Sprintf("unknown team: %s", rule.Team))
+		return
+	}
+	if !Alerts.HasChannel(rule.Channel) {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, fmt.Sprintf("unsupported channel: %s", rule.Channel))
+		return
+	}
+	if err := Alerts.ValidateTarget(r.Context(), rule.Channel, rule.Target); err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, fmt.Sprintf("invalid target: %v", err))
+		return
+	}
+
+	rule, err = Alerts.Rules.CreateRule(r.Context(), rule)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to create alert rule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// ListAlertsHandler serves GET /v1/alerts, the signed-in user's rules.
+func ListAlertsHandler(w http.ResponseWriter, r *http.Request) {
+	rules, err := Alerts.Rules.ListRules(r.Context(), currentUser(r).ID)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch alert rules")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AlertsResponse{Rules: rules})
+}
+
+// GetAlertHandler serves GET /v1/alerts/{id}.
+func GetAlertHandler(w http.ResponseWriter, r *http.Request) {
+	rule, err := ownRule(r)
+	if err == alerts.ErrRuleNotFound {
+		problem.Write(w, r, http.StatusNotFound, problem.NotFound, "alert rule not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch alert rule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteAlertHandler serves DELETE /v1/alerts/{id}.
+func DeleteAlertHandler(w http.ResponseWriter, r *http.Request) {
+	rule, err := ownRule(r)
+	if err == nil {
+		err = Alerts.Rules.DeleteRule(r.Context(), rule.ID)
+	}
+	if err == alerts.ErrRuleNotFound {
+		problem.Write(w, r, http.StatusNotFound, problem.NotFound, "alert rule not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to delete alert rule")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ownRule gets the rule in the request path if the signed-in user owns it. Other users'
+// rules are reported as not found, so their IDs can't be probed.
+func ownRule(r *http.Request) (alerts.Rule, error) {
+	rule, err := Alerts.Rules.GetRule(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return alerts.Rule{}, err
+	}
+	if rule.OwnerID != currentUser(r).ID {
+		return alerts.Rule{}, alerts.ErrRuleNotFound
+	}
+	return rule, nil
+}
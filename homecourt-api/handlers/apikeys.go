@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"homecourt-api/apikeys"
+	"homecourt-api/problem"
+	"homecourt-api/ratelimit"
+	"net/http"
+	"strconv"
+)
+
+var APIKeys apikeys.KeysManager
+
+// AdminToken guards key management. Admin routes are disabled while it's empty.
+var AdminToken string
+
+// DefaultKeyLimit applies to keys created without a limit.
+var DefaultKeyLimit = ratelimit.Limit{PerMinute: 120, Burst: 30}
+
+type CreateKeyRequest struct {
+	Name  string           `json:"name"`
+	Limit *ratelimit.Limit `json:"limit,omitempty"`
+}
+
+type CreateKeyResponse struct {
+	apikeys.Key
+	Secret string `json:"secret"`
+}
+
+type KeysResponse struct {
+	Keys []apikeys.Key `json:"keys"`
+}
+
+type UsageResponse struct {
+	KeyID string          `json:"key_id"`
+	Usage []apikeys.Usage `json:"usage"`
+}
+
+// RequireAdmin only lets through requests carrying the admin token in X-Admin-Token.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Admin-Token")
+		if AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(AdminToken)) != 1 {
+			problem.Write(w, r, http.StatusForbidden, problem.Forbidden, "forbidden")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// CreateKeyHandler serves POST /v1/apikeys. The secret is only ever returned here.
+func CreateKeyHandler(w http.ResponseWriter, r *http.Request) {
+	var req CreateKeyRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req.Name == "" {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid request body")
+		return
+	}
+
+	limit := DefaultKeyLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	if limit.PerMinute < 1 || limit.Burst < 1 {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "limit per_minute and burst must be positive")
+		return
+	}
+
+	key, secret, err := APIKeys.CreateKey(r.Context(), req.Name, limit)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to create api key")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateKeyResponse{Key: key, Secret: secret})
+}
+
+// ListKeysHandler serves GET /v1/apikeys.
+func ListKeysHandler(w http.ResponseWriter, r *http.Request) {
+	keys, err := APIKeys.ListKeys(r.Context())
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch api keys")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(KeysResponse{Keys: keys})
+}
+
+// RevokeKeyHandler serves DELETE /v1/apikeys/{id}.
+func RevokeKeyHandler(w http.ResponseWriter, r *http.Request) {
+	err := APIKeys.RevokeKey(r.Context(), r.PathValue("id"))
+	if err == apikeys.ErrKeyNotFound {
+		problem.Write(w, r, http.StatusNotFound, problem.NotFound, "api key not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to revoke api key")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// KeyUsageHandler serves GET /v1/apikeys/{id}/usage, daily request counts for the
+// last ?days= UTC days (default 7), today first.
+func KeyUsageHandler(w http.ResponseWriter, r *http.Request) {
+	keyID := r.PathValue("id")
+	_, err := APIKeys.GetKey(r.Context(), keyID)
+	if err == apikeys.ErrKeyNotFound {
+		problem.Write(w, r, http.StatusNotFound, problem.NotFound, "api key not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch api key")
+		return
+	}
+
+	days := 7
+	if d := r.URL.Query().Get("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed < 1 || parsed > 90 {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "days must be between 1 and 90")
+			return
+		}
+		days = parsed
+	}
+
+	usage, err := APIKeys.GetUsage(r.Context(), keyID, days)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch usage")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(UsageResponse{KeyID: keyID, Usage: usage})
+}
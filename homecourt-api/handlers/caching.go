@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"homecourt-api/games"
+	"homecourt-api/problem"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// writeCached responds with response as JSON, cacheable for as long as the games in it are
+// unlikely to change:
+//
+//	ETag           strong, a hash of the body, which carries each game's version
+//	Last-Modified  when a game in it last changed or had a field group observed
+//	Cache-Control  from the nearest tip-off, see cacheMaxAge
+//
+// If-None-Match and If-Modified-Since are answered with a 304 on GET and HEAD. Other methods,
+// like POST /get, always get the full body. Ranges aren't supported, the bodies are small.
+func writeCached(w http.ResponseWriter, r *http.Request, response any, gamesInBody []map[string]string) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to encode response")
+		return
+	}
+	body = append(body, '\n') // same as json.Encoder
+
+	var lastModified time.Time
+	for _, game := range gamesInBody {
+		if updatedAt := games.UpdatedAt(game); updatedAt.After(lastModified) {
+			lastModified = updatedAt
+		}
+		if observedAt := games.LastObserved(game); observedAt.After(lastModified) {
+			lastModified = observedAt
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(cacheMaxAge(gamesInBody, time.Now()).Seconds())))
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// notModified reports whether the client's copy, going by If-None-Match or, without it,
+// If-Modified-Since, is still current.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			// If-None-Match compares weakly, W/"x" matches "x"
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// the header only has whole seconds
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// cacheMaxAge is how long a response with games may be reused. The producers refresh prices
+// and odds every 10-20 seconds, but only games close to tip-off see much movement.
+func cacheMaxAge(gamesInBody []map[string]string, now time.Time) time.Duration {
+	maxAge := 5 * time.Minute
+	for _, game := range gamesInBody {
+		if game["status"] == "final" {
+			continue
+		}
+		startTime, err := time.Parse(time.RFC3339, game["start_time"])
+		if err != nil {
+			continue
+		}
+
+		var age time.Duration
+		switch untilTipOff := startTime.Sub(now); {
+		case untilTipOff < time.Hour:
+			// about to start or being played, scores change too
+			age = 5 * time.Second
+		case untilTipOff < 24*time.Hour:
+			age = 15 * time.Second
+		case untilTipOff < 7*24*time.Hour:
+			age = time.Minute
+		default:
+			continue
+		}
+		maxAge = min(maxAge, age)
+	}
+	return maxAge
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWriteCached(t *testing.T) {
+	updatedAt := time.Date(2030, 1, 10, 18, 0, 0, 0, time.UTC)
+	game := map[string]string{"home_team": "NYK", "away_team": "BOS", "updated_at": updatedAt.Format(time.RFC3339)}
+	serve := func(method string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/get", nil)
+		r.Header = header
+		w := httptest.NewRecorder()
+		writeCached(w, r, game, []map[string]string{game})
+		return w
+	}
+
+	first := serve(http.MethodGet, http.Header{})
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("got %d with ETag %q, want 200 with an ETag", first.Code, etag)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		header http.Header
+		status int
+	}{
+		{"matching etag", http.MethodGet, http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+		{"weak matching etag", http.MethodGet, http.Header{"If-None-Match": {`"other", W/` + etag}}, http.StatusNotModified},
+		{"changed etag", http.MethodGet, http.Header{"If-None-Match": {`"other"`}}, http.StatusOK},
+		{"etag wins over date", http.MethodGet, http.Header{"If-None-Match": {`"other"`}, "If-Modified-Since": {updatedAt.Format(http.TimeFormat)}}, http.StatusOK},
+		{"not modified since", http.MethodGet, http.Header{"If-Modified-Since": {updatedAt.Format(http.TimeFormat)}}, http.StatusNotModified},
+		{"modified since", http.MethodGet, http.Header{"If-Modified-Since": {updatedAt.Add(-time.Hour).Format(http.TimeFormat)}}, http.StatusOK},
+		{"post ignores etag", http.MethodPost, http.Header{"If-None-Match": {etag}}, http.StatusOK},
+		{"post ignores if-match", http.MethodPost, http.Header{"If-Match": {`"other"`}}, http.StatusOK},
+		{"range gets the whole body", http.MethodGet, http.Header{"Range": {"bytes=0-4"}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.method, tt.header)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && w.Body.String() != first.Body.String() {
+				t.Errorf("body = %q, want %q", w.Body.String(), first.Body.String())
+			}
+		})
+	}
+}
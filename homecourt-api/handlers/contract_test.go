@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"homecourt-api/games"
+	"homecourt-api/openapi"
+	"homecourt-api/problem"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGames keeps upcoming and archived games in memory. Methods the handlers under test
+// don't call are left to the embedded nil interface.
+type fakeGames struct {
+	games.GamesManager
+	upcoming map[string]map[string]string
+	archived map[string]map[string]string
+	// games GetGames fails to read, like a hash that's gone bad
+	broken map[string]bool
+}
+
+func (f *fakeGames) GetUpcomingGames(ctx context.Context, teamID string, count int64) ([]string, error) {
+	var gameIDs []string
+	for gameID, game := range f.upcoming {
+		if game["home_team"] == teamID {
+			gameIDs = append(gameIDs, gameID)
+		}
+	}
+	for gameID := range f.broken {
+		gameIDs = append(gameIDs, gameID)
+	}
+	sort.Strings(gameIDs)
+	if count >= 0 && int64(len(gameIDs)) > count {
+		gameIDs = gameIDs[:count]
+	}
+	return gameIDs, nil
+}
+
+func (f *fakeGames) GetGames(ctx context.Context, gameIDs []string) ([]games.GameResult, error) {
+	results := make([]games.GameResult, len(gameIDs))
+	for i, gameID := range gameIDs {
+		results[i] = games.GameResult{GameID: gameID}
+		if f.broken[gameID] {
+			results[i].Err = fmt.Errorf("failed to get game data: %w", games.ErrUnavailable)
+			continue
+		}
+		results[i].Data = copyGame(f.upcoming[gameID])
+	}
+	return results, nil
+}
+
+func (f *fakeGames) GetGame(ctx context.Context, gameID string) (map[string]string, error) {
+	game, ok := f.upcoming[gameID]
+	if !ok {
+		return nil, fmt.Errorf("game %s: %w", gameID, games.ErrGameNotFound)
+	}
+	return copyGame(game), nil
+}
+
+func (f *fakeGames) GetArchivedGame(ctx context.Context, gameID string) (map[string]string, error) {
+	game, ok := f.archived[gameID]
+	if !ok {
+		return nil, fmt.Errorf("archived game %s: %w", gameID, games.ErrGameNotFound)
+	}
+	return copyGame(game), nil
+}
+
+func (f *fakeGames) GetArchivedGames(ctx context.Context, gameIDs []string) ([]games.GameResult, error) {
+	results := make([]games.GameResult, len(gameIDs))
+	for i, gameID := range gameIDs {
+		results[i] = games.GameResult{GameID: gameID}
+		results[i].Data, results[i].Err = f.GetArchivedGame(ctx, gameID)
+	}
+	return results, nil
+}
+
+func (f *fakeGames) GetPastGames(ctx context.Context, teamID string, count int64) ([]string, error) {
+	var gameIDs []string
+	for gameID, game := range f.archived {
+		if game["home_team"] == teamID || game["away_team"] == teamID {
+			gameIDs = append(gameIDs, gameID)
+		}
+	}
+	sort.Strings(gameIDs)
+	return gameIDs, nil
+}
+
+func (f *fakeGames) FindGames(ctx context.Context, query games.GameQuery) ([]string, error) {
+	var gameIDs []string
+	for gameID := range f.upcoming {
+		gameIDs = append(gameIDs, gameID)
+	}
+	sort.Strings(gameIDs)
+	return gameIDs, nil
+}
+
+func copyGame(game map[string]string) map[string]string {
+	if game == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(game))
+	for key, value := range game {
+		copied[key] = value
+	}
+	return copied
+}
+
+func newFakeGames(now time.Time) *fakeGames {
+	observed := now.Add(-time.Minute).Format(time.RFC3339)
+	return &fakeGames{
+		upcoming: map[string]map[string]string{
+			"NYK BOS 01.10.2030": {
+				"home_team":           "NYK",
+				"away_team":           "BOS",
+				"venueName":           "Madison Square Garden, New York, NY",
+				"start_time":          now.Add(3 * 24 * time.Hour).Format(time.RFC3339),
+				"lowest_ticket_price": "$120.00",
+				"home_team_odds":      "-150",
+				"injured_players":     `[{"team":"BOS","player_name":"Jayson Tatum","status":"Out"}]`,
+				"status":              "scheduled",
+				"version":             "3",
+				"updated_at":          observed,
+				"tickets_observed_at": observed,
+				"tickets_source":      "ticketmaster",
+				"odds_observed_at":    observed,
+				"odds_source":         "oddsblaze",
+			},
+			"NYK MIA 01.12.2030": {
+				"home_team":           "NYK",
+				"away_team":           "MIA",
+				"venueName":           "Madison Square Garden, New York, NY",
+				"start_time":          now.Add(5 * 24 * time.Hour).Format(time.RFC3339),
+				"lowest_ticket_price": "$85.00",
+				"home_team_odds":      "+110",
+				"status":              "scheduled",
+			},
+		},
+		archived: map[string]map[string]string{
+			"NYK DAL 11.28.2024": {
+				"home_team":             "NYK",
+				"away_team":             "DAL",
+				"venueName":             "Madison Square Garden, New York, NY",
+				"start_time":            "2024-11-28T00:30:00Z",
+				"end_time":              "2024-11-28T03:00:00Z",
+				"lowest_ticket_price":   "$99.00",
+				"home_team_odds":        "+135",
+				"status":                "final",
+				"home_score":            "112",
+				"away_score":            "104",
+				"winner":                "NYK",
+				"home_team_odds_result": "won",
+			},
+		},
+		broken: map[string]bool{"NYK LAL 01.15.2030": true},
+	}
+}
+
+// newContractServer routes the game handlers like main.go does, behind the strict validator,
+// so any response that drifts from the spec comes back as a contract violation.
+func newContractServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	Manager = newFakeGames(time.Now())
+	t.Cleanup(func() { Manager = nil })
+
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, err := openapi.NewValidator(doc, openapi.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /get", GetHandler)
+	mux.HandleFunc("GET /v1/teams/{abbr}/results", ResultsHandler)
+	mux.HandleFunc("GET /v1/teams/{abbr}/recommendations", RecommendationsHandler)
+	mux.HandleFunc("GET /v1/games", GamesHandler)
+	mux.HandleFunc("GET /v1/games/{id}", GameHandler)
+
+	server := httptest.NewServer(validator.Handler(mux))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandlersMatchContract(t *testing.T) {
+	server := newContractServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"upcoming home games", "POST", "/get", `{"Team":"NYK"}`, http.StatusOK, ""},
+		{"upcoming home games in a timezone", "POST", "/get?tz=America/Los_Angeles", `{"Team":"NYK"}`, http.StatusOK, ""},
+		{"upcoming home games, invalid timezone", "POST", "/get?tz=Mars/Olympus", `{"Team":"NYK"}`, http.StatusBadRequest, problem.InvalidRequest},
+		{"upcoming home games, team not in the spec", "POST", "/get", `{"Team":"knicks"}`, http.StatusBadRequest, problem.InvalidRequest},
+		{"results", "GET", "/v1/teams/NYK/results", "", http.StatusOK, ""},
+		{"results, limited", "GET", "/v1/teams/DAL/results?limit=1", "", http.StatusOK, ""},
+		{"results, unknown team", "GET", "/v1/teams/XYZ/results", "", http.StatusNotFound, problem.InvalidTeam},
+		{"results, invalid limit", "GET", "/v1/teams/NYK/results?limit=0", "", http.StatusBadRequest, problem.InvalidRequest},
+		{"recommendations", "GET", "/v1/teams/NYK/recommendations", "", http.StatusOK, ""},
+		{"recommendations with weights", "GET", "/v1/teams/NYK/recommendations?weights=price=0.6,stars=0.4&limit=1", "", http.StatusOK, ""},
+		{"recommendations, invalid weights", "GET", "/v1/teams/NYK/recommendations?weights=vibes=1", "", http.StatusBadRequest, problem.InvalidRequest},
+		{"games", "GET", "/v1/games", "", http.StatusOK, ""},
+		{"games, paged", "GET", "/v1/games?limit=1&sort=price&order=desc", "", http.StatusOK, ""},
+		{"games, invalid order", "GET", "/v1/games?order=sideways", "", http.StatusBadRequest, problem.InvalidRequest},
+		{"upcoming game", "GET", "/v1/games/NYK%20BOS%2001.10.2030", "", http.StatusOK, ""},
+		{"archived game", "GET", "/v1/games/NYK%20DAL%2011.28.2024", "", http.StatusOK, ""},
+		{"unknown game", "GET", "/v1/games/NYK%20DAL%2001.01.1999", "", http.StatusNotFound, problem.GameNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode == http.StatusOK {
+				if tt.status != http.StatusOK {
+					t.Fatalf("status = 200, want %d", tt.status)
+				}
+				return
+			}
+			var p problem.Problem
+			if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+				t.Fatalf("status = %d with an unreadable problem: %v", resp.StatusCode, err)
+			}
+			if p.Code == problem.ContractViolation {
+				t.Fatalf("response doesn't match the spec: %s", p.Detail)
+			}
+			if resp.StatusCode != tt.status || p.Code != tt.code {
+				t.Fatalf("got %d %s (%s), want %d %s", resp.StatusCode, p.Code, p.Detail, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestPartialFailuresMatchContract(t *testing.T) {
+	server := newContractServer(t)
+
+	resp, err := http.Post(server.URL+"/get", "application/json", strings.NewReader(`{"Team":"NYK"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var body GetResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Games) != 2 {
+		t.Errorf("got %d games, want 2", len(body.Games))
+	}
+	if len(body.Errors) != 1 || body.Errors[0].GameID != "NYK LAL 01.15.2030" || body.Errors[0].Code != problem.Unavailable {
+		t.Errorf("errors = %+v, want the broken game reported as unavailable", body.Errors)
+	}
+}
+
+func TestContractErrorsLeaveOutSchemas(t *testing.T) {
+	server := newContractServer(t)
+
+	resp, err := http.Post(server.URL+"/get", "application/json", strings.NewReader(`{"Team":42}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var p problem.Problem
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+	if !strings.Contains(p.Detail, `"/Team"`) || strings.Contains(p.Detail, "Schema:") {
+		t.Errorf("detail = %q, want the field's path without the schema", p.Detail)
+	}
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"homecourt-api/games"
+	"homecourt-api/problem"
+	"homecourt-api/teams"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGamesLimit = 20
+	maxGamesLimit     = 100
+)
+
+type GamesResponse struct {
+	Games      []map[string]string `json:"games"`
+	Errors     []GameError         `json:"errors,omitempty"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// GamesHandler serves GET /v1/games: upcoming games across the league.
+//
+//	from, to       RFC3339 instants or YYYY-MM-DD dates (to is inclusive), from defaults to now
+//	team           either side of the game
+//	venue          case-insensitive substring of the venue name
+//	max_price      highest lowest_ticket_price, in dollars
+//	home           favorite or underdog, by the home team's moneyline
+//	has_injuries   false only, injury reports aren't stored yet
+//	sort           tip_off (default), price or odds; order=desc reverses it
+//	limit, cursor  page size (default 20, max 100) and next_cursor from the previous page
+//	tz             as on /get
+func GamesHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	var userLocation *time.Location
+	if tz := params.Get("tz"); tz != "" {
+		var err error
+		userLocation, err = time.LoadLocation(tz)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, fmt.Sprintf("invalid tz: %s", tz))
+			return
+		}
+	}
+
+	query, err := parseGameQuery(params.Get, userLocation)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	page, nextCursor, failed, err := games.Search(ctx, Manager, query)
+	if err != nil {
+		problem.Error(w, r, err, "failed to search games")
+		return
+	}
+	var gameErrors []GameError
+	for _, result := range failed {
+		gameErrors = append(gameErrors, newGameError(ctx, result))
+	}
+	for _, game := range page {
+		localizeGame(game, userLocation)
+		games.MarkStale(game, StaleAfter, time.Now())
+	}
+
+	writeCached(w, r, GamesResponse{Games: page, Errors: gameErrors, NextCursor: nextCursor}, page)
+}
+
+// GameHandler serves GET /v1/games/{id}: a single game, upcoming or archived. ?tz= as on /get.
+func GameHandler(w http.ResponseWriter, r *http.Request) {
+	gameID := r.PathValue("id")
+
+	var userLocation *time.Location
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		var err error
+		userLocation, err = time.LoadLocation(tz)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, fmt.Sprintf("invalid tz: %s", tz))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	game, err := Manager.GetGame(ctx, gameID)
+	archived := errors.Is(err, games.ErrGameNotFound)
+	if archived {
+		game, err = Manager.GetArchivedGame(ctx, gameID)
+	}
+	if errors.Is(err, games.ErrGameNotFound) {
+		problem.Write(w, r, http.StatusNotFound, problem.GameNotFound, fmt.Sprintf("game not found: %s", gameID))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err, fmt.Sprintf("failed to fetch game %s", gameID))
+		return
+	}
+
+	game["game_id"] = gameID
+	localizeGame(game, userLocation)
+	if !archived {
+		games.MarkStale(game, StaleAfter, time.Now())
+	}
+	writeCached(w, r, game, []map[string]string{game})
+}
+
+// parseGameQuery builds a GameQuery from query parameters. Plain dates are read in loc,
+// or UTC when it's nil.
+func parseGameQuery(get func(string) string, loc *time.Location) (games.GameQuery, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	query := games.GameQuery{
+		From:     time.Now(),
+		Team:     strings.ToUpper(get("team")),
+		Venue:    get("venue"),
+		HomeLine: get("home"),
+		Sort:     get("sort"),
+		Limit:    defaultGamesLimit,
+		Cursor:   get("cursor"),
+	}
+
+	var err error
+	if from := get("from"); from != "" {
+		query.From, err = parseQueryTime(from, loc, false)
+		if err != nil {
+			return query, fmt.Errorf("invalid from: %s", from)
+		}
+	}
+	if to := get("to"); to != "" {
+		query.To, err = parseQueryTime(to, loc, true)
+		if err != nil {
+			return query, fmt.Errorf("invalid to: %s", to)
+		}
+	}
+
+	if query.Team != "" {
+		if _, ok := teams.Registry[query.Team]; !ok {
+			return query, fmt.Errorf("unknown team: %s", query.Team)
+		}
+	}
+
+	if maxPrice := get("max_price"); maxPrice != "" {
+		query.MaxPrice, err = strconv.ParseFloat(strings.TrimPrefix(maxPrice, "$"), 64)
+		if err != nil || query.MaxPrice <= 0 {
+			return query, fmt.Errorf("invalid max_price: %s", maxPrice)
+		}
+	}
+
+	if injuries := get("has_injuries"); injuries != "" {
+		hasInjuries, err := strconv.ParseBool(injuries)
+		if err != nil {
+			return query, fmt.Errorf("invalid has_injuries: %s", injuries)
+		}
+		query.HasInjuries = &hasInjuries
+	}
+
+	switch order := get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		return query, fmt.Errorf("invalid order: %s", order)
+	}
+
+	if l := get("limit"); l != "" {
+		query.Limit, err = strconv.Atoi(l)
+		if err != nil || query.Limit < 1 || query.Limit > maxGamesLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxGamesLimit)
+		}
+	}
+
+	return query, query.Validate()
+}
+
+// parseQueryTime accepts an RFC3339 instant or a YYYY-MM-DD date in loc. A date used as
+// the end of a range covers the whole day.
+func parseQueryTime(value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		return date.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return date, nil
+}
@@ -3,9 +3,15 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"homecourt-api/games"
+	"homecourt-api/problem"
+	"homecourt-api/teams"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type GetRequest struct {
@@ -14,41 +20,173 @@ type GetRequest struct {
 
 var Manager games.GamesManager
 
+// StaleAfter is how old each group of a game's fields may be before it's flagged
+// <group>_stale in responses.
+var StaleAfter = games.DefaultStaleAfter
+
+// how long a request may spend reading games from Redis
+const lookupTimeout = 2 * time.Second
+
 type GetResponse struct {
 	Games []map[string]string `json:"games"`
+	// games that couldn't be fetched, the rest of the response is still served
+	Errors []GameError `json:"errors,omitempty"`
+}
+
+type GameError struct {
+	GameID string `json:"game_id"`
+	Code   string `json:"code"`
+	Error  string `json:"error"`
+}
+
+// newGameError reports a game GetGames couldn't return, without the underlying error.
+func newGameError(ctx context.Context, result games.GameResult) GameError {
+	_, code := problem.Classify(result.Err)
+	if code == problem.GameNotFound {
+		return GameError{GameID: result.GameID, Code: code, Error: "game not found"}
+	}
+	slog.ErrorContext(ctx, "error fetching game", "game_id", result.GameID, "err", result.Err)
+	return GameError{GameID: result.GameID, Code: code, Error: "failed to fetch game data"}
+}
+
+// fetchGames looks up gameIDs in one round trip and localizes them. Games that fail are
+// reported as GameErrors rather than failing the whole response.
+func fetchGames(ctx context.Context, gameIDs []string, loc *time.Location) ([]map[string]string, []GameError, error) {
+	results, err := Manager.GetGames(ctx, gameIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fetched := []map[string]string{}
+	var gameErrors []GameError
+	for _, result := range results {
+		if result.Err != nil {
+			gameErrors = append(gameErrors, newGameError(ctx, result))
+			continue
+		}
+		localizeGame(result.Data, loc)
+		games.MarkStale(result.Data, StaleAfter, time.Now())
+		fetched = append(fetched, result.Data)
+	}
+	return fetched, gameErrors, nil
 }
 
 func GetHandler(w http.ResponseWriter, r *http.Request) {
 	var req GetRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid request body")
 		return
 	}
 
+	// optional ?tz=America/New_York renders tip-off in the caller's zone instead of the arena's
+	var userLocation *time.Location
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		userLocation, err = time.LoadLocation(tz)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, fmt.Sprintf("invalid tz: %s", tz))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
 	team := req.Team
-	upcomingGamesKeys, err := Manager.GetUpcomingGames(context.Background(), team, 5)
+	upcomingGamesKeys, err := Manager.GetUpcomingGames(ctx, team, 5)
 	if err != nil {
-		http.Error(w, "failed to fetch upcoming games", http.StatusInternalServerError)
+		problem.Error(w, r, err, "failed to fetch upcoming games")
 		return
 	}
 
-	var games []map[string]string
+	games, gameErrors, err := fetchGames(ctx, upcomingGamesKeys, userLocation)
+	if err != nil {
+		problem.Error(w, r, err, "failed to fetch game data")
+		return
+	}
 
-	for _, gameID := range upcomingGamesKeys {
-		gameData, err := Manager.GetGame(context.Background(), gameID)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to fetch game data for key: %s", gameID), http.StatusInternalServerError)
+	// Construct response
+	response := GetResponse{
+		Games:  games,
+		Errors: gameErrors,
+	}
+
+	writeCached(w, r, response, games)
+}
+
+type ResultsResponse struct {
+	Team  string              `json:"team"`
+	Games []map[string]string `json:"games"`
+}
+
+// ResultsHandler serves GET /v1/teams/{abbr}/results: a team's most recent finished games,
+// home and away, newest first. ?limit= caps the number of games (default 10).
+func ResultsHandler(w http.ResponseWriter, r *http.Request) {
+	team := r.PathValue("abbr")
+	if _, ok := teams.Registry[team]; !ok {
+		problem.Write(w, r, http.StatusNotFound, problem.InvalidTeam, fmt.Sprintf("unknown team: %s", team))
+		return
+	}
+
+	limit := int64(10)
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || parsed < 1 {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid limit")
 			return
 		}
-		games = append(games, gameData)
+		limit = parsed
 	}
 
-	// Construct response
-	response := GetResponse{
-		Games: games,
+	pastGameIDs, err := Manager.GetPastGames(r.Context(), team, limit)
+	if err != nil {
+		problem.Error(w, r, err, "failed to fetch past games")
+		return
+	}
+
+	results, err := Manager.GetArchivedGames(r.Context(), pastGameIDs)
+	if err != nil {
+		problem.Error(w, r, err, "failed to fetch past games")
+		return
+	}
+
+	pastGames := []map[string]string{}
+	for _, result := range results {
+		// every archive adds refreshes the index TTL, so it outlives the oldest archived hashes
+		if errors.Is(result.Err, games.ErrGameNotFound) {
+			continue
+		}
+		if result.Err != nil {
+			problem.Error(w, r, result.Err, fmt.Sprintf("failed to fetch game %s", result.GameID))
+			return
+		}
+		// no staleness flags, the feeds are done with archived games
+		localizeGame(result.Data, nil)
+		pastGames = append(pastGames, result.Data)
+	}
+
+	writeCached(w, r, ResultsResponse{Team: team, Games: pastGames}, pastGames)
+}
+
+// localizeGame adds the tip-off as a UTC instant and as local time to a game.
+// Local time is in loc when given, otherwise in the home arena's timezone.
+// Games without a parseable start_time are left untouched.
+func localizeGame(game map[string]string, loc *time.Location) {
+	startTime, err := time.Parse(time.RFC3339, game["start_time"])
+	if err != nil {
+		return
+	}
+
+	arenaLocation, err := teams.Location(game["home_team"])
+	if err != nil {
+		arenaLocation = time.UTC
+	}
+	if loc == nil {
+		loc = arenaLocation
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	game["arena_timezone"] = arenaLocation.String()
+	game["tip_off_utc"] = startTime.UTC().Format(time.RFC3339)
+	game["tip_off_local"] = startTime.In(loc).Format(time.RFC3339)
+	game["tip_off_timezone"] = loc.String()
 }
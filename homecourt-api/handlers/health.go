@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"homecourt-api/health"
+	"homecourt-api/problem"
+	"net/http"
+)
+
+var Health *health.Checker
+
+// HealthzHandler serves GET /healthz: the process is up and serving. It doesn't look at
+// Redis or RabbitMQ, restarting the API wouldn't fix either.
+func HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// ReadyzHandler serves GET /readyz: 200 while Redis answers and the receiver is consuming,
+// 503 otherwise. The body says which check failed. Stale sources only show on /v1/status.
+func ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	report := Health.Check(ctx)
+
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// StatusHandler serves GET /v1/status: the readiness checks along with when each source
+// (tickets, odds, injuries, results, schedule) last delivered data and whether it's stale.
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	report := Health.Status(ctx)
+	if report.Sources == nil {
+		problem.Write(w, r, http.StatusServiceUnavailable, problem.Unavailable, "failed to fetch source updates")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"homecourt-api/games"
+	"homecourt-api/problem"
+	"homecourt-api/recommendations"
+	"homecourt-api/teams"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RecommendationWeights are used unless a request passes its own ?weights=
+var RecommendationWeights = recommendations.DefaultWeights
+
+// how many of a team's latest archived games, home and away, are read for its median
+// ticket price. Only the home games count.
+const priceHistoryGames = 82
+
+type RecommendationsResponse struct {
+	Team            string                           `json:"team"`
+	MedianPrice     float64                          `json:"median_price"`
+	Weights         recommendations.Weights          `json:"weights"`
+	Recommendations []recommendations.Recommendation `json:"recommendations"`
+	Errors          []GameError                      `json:"errors,omitempty"`
+}
+
+// RecommendationsHandler serves GET /v1/teams/{abbr}/recommendations: the team's upcoming
+// home games ranked by how worth attending they are, with each game's score broken down.
+// ?weights=price=0.6,stars=0.4 overrides the configured weights, ?limit= caps the games
+// (default 5), and ?tz= works as on /get.
+func RecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	team := r.PathValue("abbr")
+	if _, ok := teams.Registry[team]; !ok {
+		problem.Write(w, r, http.StatusNotFound, problem.InvalidTeam, fmt.Sprintf("unknown team: %s", team))
+		return
+	}
+
+	params := r.URL.Query()
+	weights := RecommendationWeights
+	if s := params.Get("weights"); s != "" {
+		var err error
+		weights, err = recommendations.ParseWeights(s)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, err.Error())
+			return
+		}
+	}
+
+	limit := 5
+	if l := params.Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	var userLocation *time.Location
+	if tz := params.Get("tz"); tz != "" {
+		var err error
+		userLocation, err = time.LoadLocation(tz)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, fmt.Sprintf("invalid tz: %s", tz))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	// every upcoming home game is scored before the best ones are picked
+	gameIDs, err := Manager.GetUpcomingGames(ctx, team, -1)
+	if err != nil {
+		problem.Error(w, r, err, "failed to fetch upcoming games")
+		return
+	}
+	results, err := Manager.GetGames(ctx, gameIDs)
+	if err != nil {
+		problem.Error(w, r, err, "failed to fetch game data")
+		return
+	}
+
+	var upcoming []games.GameResult
+	var gameErrors []GameError
+	for _, result := range results {
+		if result.Err != nil {
+			gameErrors = append(gameErrors, newGameError(ctx, result))
+			continue
+		}
+		if len(result.Data) == 0 {
+			continue
+		}
+		upcoming = append(upcoming, result)
+	}
+
+	pastPrices, err := pastHomePrices(ctx, team)
+	if err != nil {
+		// the price factor just drops out without history, no reason to fail the request
+		slog.WarnContext(ctx, "error fetching price history", "team", team, "err", err)
+	}
+	// the games being scored stay out of it, they'd only be compared against themselves. No
+	// history means a median of 0, which leaves the price factor out.
+	medianPrice := recommendations.Median(pastPrices)
+
+	ranked := []recommendations.Recommendation{}
+	for _, result := range upcoming {
+		ranked = append(ranked, recommendations.Score(result.GameID, result.Data, medianPrice, weights))
+	}
+	recommendations.Rank(ranked)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	for _, recommendation := range ranked {
+		localizeGame(recommendation.Game, userLocation)
+		games.MarkStale(recommendation.Game, StaleAfter, time.Now())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RecommendationsResponse{
+		Team:            team,
+		MedianPrice:     medianPrice,
+		Weights:         weights,
+		Recommendations: ranked,
+		Errors:          gameErrors,
+	})
+}
+
+// pastHomePrices returns the final lowest ticket prices of the team's recently archived
+// home games. Games that couldn't be read are left out and reported in the error.
+func pastHomePrices(ctx context.Context, team string) ([]float64, error) {
+	pastGameIDs, err := Manager.GetPastGames(ctx, team, priceHistoryGames)
+	if err != nil {
+		return nil, err
+	}
+	results, err := Manager.GetArchivedGames(ctx, pastGameIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []float64
+	var errs []error
+	for _, result := range results {
+		if errors.Is(result.Err, games.ErrGameNotFound) {
+			continue
+		}
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		if result.Data["home_team"] != team {
+			continue
+		}
+		if price, err := games.ParsePrice(result.Data["lowest_ticket_price"]); err == nil {
+			prices = append(prices, price)
+		}
+	}
+	return prices, errors.Join(errs...)
+}
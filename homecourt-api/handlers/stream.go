@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"homecourt-api/events"
+	"homecourt-api/problem"
+	"homecourt-api/teams"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var Events *events.Hub
+
+// keeps proxies from closing idle streams
+const streamHeartbeat = 15 * time.Second
+
+// StreamHandler serves GET /v1/stream?teams=DAL,NYK as server-sent events. Every change the
+// receiver makes to a game involving one of the teams is pushed as an event named after its
+// type (e.g. game.price_changed) with the GameEvent as JSON data. Omitting teams streams every game.
+func StreamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "streaming unsupported")
+		return
+	}
+
+	var subscribedTeams []string
+	if param := r.URL.Query().Get("teams"); param != "" {
+		for _, team := range strings.Split(param, ",") {
+			team = strings.ToUpper(strings.TrimSpace(team))
+			if _, ok := teams.Registry[team]; !ok {
+				problem.Write(w, r, http.StatusBadRequest, problem.InvalidTeam, fmt.Sprintf("unknown team: %s", team))
+				return
+			}
+			subscribedTeams = append(subscribedTeams, team)
+		}
+	}
+
+	gameEvents, unsubscribe := Events.Subscribe(subscribedTeams)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case event, ok := <-gameEvents:
+			// the hub is shutting down
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "error marshalling event", "type", event.Type, "err", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
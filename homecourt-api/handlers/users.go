@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"homecourt-api/games"
+	"homecourt-api/problem"
+	"homecourt-api/teams"
+	"homecourt-api/users"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+var Users users.UsersManager
+
+type userContextKey struct{}
+
+type CredentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type SessionResponse struct {
+	Token string     `json:"token"`
+	User  users.User `json:"user"`
+}
+
+type FavoritesRequest struct {
+	Teams []string `json:"teams"`
+}
+
+// bearerToken returns the token from an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// RequireUser rejects requests without a valid session and makes the user available
+// to next through currentUser.
+func RequireUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if token == "" {
+			problem.Write(w, r, http.StatusUnauthorized, problem.Unauthorized, "missing bearer token")
+			return
+		}
+
+		user, err := Users.GetSessionUser(r.Context(), token)
+		if err == users.ErrInvalidSession {
+			problem.Write(w, r, http.StatusUnauthorized, problem.Unauthorized, "invalid or expired session")
+			return
+		}
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch session")
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user)))
+	}
+}
+
+func currentUser(r *http.Request) users.User {
+	user, _ := r.Context().Value(userContextKey{}).(users.User)
+	return user
+}
+
+func writeSession(w http.ResponseWriter, r *http.Request, user users.User, status int) {
+	token, err := Users.CreateSession(r.Context(), user.ID)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to create session")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(SessionResponse{Token: token, User: user.Public()})
+}
+
+// SignupHandler serves POST /v1/signup, creating an account and logging it in.
+func SignupHandler(w http.ResponseWriter, r *http.Request) {
+	var req CredentialsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid request body")
+		return
+	}
+
+	err = users.ValidateSignup(req.Email, req.Password)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, err.Error())
+		return
+	}
+
+	user, err := Users.CreateUser(r.Context(), req.Email, req.Password)
+	if err == users.ErrEmailTaken {
+		problem.Write(w, r, http.StatusConflict, problem.Conflict, err.Error())
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to create user")
+		return
+	}
+
+	writeSession(w, r, user, http.StatusCreated)
+}
+
+// LoginHandler serves POST /v1/login.
+func LoginHandler(w http.ResponseWriter, r *http.Request) {
+	var req CredentialsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid request body")
+		return
+	}
+
+	user, err := Users.Authenticate(r.Context(), req.Email, req.Password)
+	if err == users.ErrInvalidCredentials {
+		problem.Write(w, r, http.StatusUnauthorized, problem.Unauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to log in")
+		return
+	}
+
+	writeSession(w, r, user, http.StatusOK)
+}
+
+// LogoutHandler serves POST /v1/logout, ending the session the request was made with.
+func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	err := Users.DeleteSession(r.Context(), bearerToken(r))
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to log out")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MeHandler serves GET /v1/me.
+func MeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(currentUser(r).Public())
+}
+
+// FavoritesHandler serves PUT /v1/me/favorites, replacing the user's favorite teams.
+func FavoritesHandler(w http.ResponseWriter, r *http.Request) {
+	var req FavoritesRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid request body")
+		return
+	}
+
+	favorites := []string{}
+	seen := make(map[string]bool)
+	for _, team := range req.Teams {
+		team = strings.ToUpper(strings.TrimSpace(team))
+		if _, ok := teams.Registry[team]; !ok {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidTeam, fmt.Sprintf("unknown team: %s", team))
+			return
+		}
+		if !seen[team] {
+			seen[team] = true
+			favorites = append(favorites, team)
+		}
+	}
+
+	user, err := Users.SetFavoriteTeams(r.Context(), currentUser(r).ID, favorites)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to save favorite teams")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user.Public())
+}
+
+// how many upcoming games of each favorite team /v1/me/games lists
+const myGamesPerTeam = 5
+
+// MyGamesHandler serves GET /v1/me/games: the upcoming home and away games of every
+// favorite team merged into one list sorted by tip-off. Accepts the same ?tz= as /get.
+func MyGamesHandler(w http.ResponseWriter, r *http.Request) {
+	var userLocation *time.Location
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		var err error
+		userLocation, err = time.LoadLocation(tz)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, fmt.Sprintf("invalid tz: %s", tz))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	// team:<abbr>:games has a team's away games too, the upcoming list only its home ones
+	var gameIDs []string
+	seen := map[string]bool{}
+	from := time.Now().Add(-games.GameLength)
+	for _, team := range currentUser(r).FavoriteTeams {
+		teamGameIDs, err := Manager.FindGames(ctx, games.GameQuery{Team: team, From: from, Limit: myGamesPerTeam})
+		if err != nil {
+			problem.Error(w, r, err, "failed to fetch upcoming games")
+			return
+		}
+		if len(teamGameIDs) > myGamesPerTeam {
+			teamGameIDs = teamGameIDs[:myGamesPerTeam]
+		}
+		for _, gameID := range teamGameIDs {
+			// two favorites playing each other
+			if !seen[gameID] {
+				seen[gameID] = true
+				gameIDs = append(gameIDs, gameID)
+			}
+		}
+	}
+
+	games, gameErrors, err := fetchGames(ctx, gameIDs, userLocation)
+	if err != nil {
+		problem.Error(w, r, err, "failed to fetch game data")
+		return
+	}
+
+	// start_time is RFC3339 UTC, so it sorts as a string
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i]["start_time"] < games[j]["start_time"]
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GetResponse{Games: games, Errors: gameErrors})
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"homecourt-api/problem"
+	"homecourt-api/webhooks"
+	"net/http"
+	"strconv"
+)
+
+var Webhooks webhooks.WebhooksManager
+
+type WebhooksResponse struct {
+	Webhooks []webhooks.Webhook `json:"webhooks"`
+}
+
+type DeliveriesResponse struct {
+	Deliveries []webhooks.Delivery `json:"deliveries"`
+}
+
+// CreateWebhookHandler serves POST /v1/webhooks. The response is the only place the
+// signing secret is returned.
+func CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var webhook webhooks.Webhook
+	err := json.NewDecoder(r.Body).Decode(&webhook)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid request body")
+		return
+	}
+
+	err = webhook.Validate(r.Context())
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, err.Error())
+		return
+	}
+
+	webhook, err = Webhooks.CreateWebhook(r.Context(), webhook)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to create webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// ListWebhooksHandler serves GET /v1/webhooks.
+func ListWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	registered, err := Webhooks.ListWebhooks(r.Context())
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch webhooks")
+		return
+	}
+	for i := range registered {
+		registered[i].Secret = ""
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(WebhooksResponse{Webhooks: registered})
+}
+
+// GetWebhookHandler serves GET /v1/webhooks/{id}.
+func GetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, err := Webhooks.GetWebhook(r.Context(), r.PathValue("id"))
+	if err == webhooks.ErrWebhookNotFound {
+		problem.Write(w, r, http.StatusNotFound, problem.NotFound, "webhook not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch webhook")
+		return
+	}
+	webhook.Secret = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// DeleteWebhookHandler serves DELETE /v1/webhooks/{id}.
+func DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	err := Webhooks.DeleteWebhook(r.Context(), r.PathValue("id"))
+	if err == webhooks.ErrWebhookNotFound {
+		problem.Write(w, r, http.StatusNotFound, problem.NotFound, "webhook not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to delete webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// WebhookDeliveriesHandler serves GET /v1/webhooks/{id}/deliveries, the most recent delivery
+// attempts newest first. ?limit= caps the number of attempts (default 20).
+func WebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID := r.PathValue("id")
+	_, err := Webhooks.GetWebhook(r.Context(), webhookID)
+	if err == webhooks.ErrWebhookNotFound {
+		problem.Write(w, r, http.StatusNotFound, problem.NotFound, "webhook not found")
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch webhook")
+		return
+	}
+
+	limit := int64(20)
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || parsed < 1 {
+			problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := Webhooks.GetDeliveries(r.Context(), webhookID, limit)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to fetch deliveries")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(DeliveriesResponse{Deliveries: deliveries})
+}
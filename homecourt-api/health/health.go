@@ -0,0 +1,172 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"homecourt-api/games"
+	"homecourt-api/metrics"
+	"homecourt-api/tracing"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SourcesKey is a hash of when each source last delivered data that was stored, as RFC3339.
+// The receiver updates it for its queues and homecourt-init for the schedule, so every
+// replica sees the same times.
+const SourcesKey = "sources:last_updated"
+
+// Sources are the feeds whose freshness is tracked, one for each group of game fields.
+var Sources = games.Groups
+
+// DefaultStaleAfter covers the feeds that are polled around the clock, each a little longer
+// than the slowest cadence homecourt-stream's scheduler fetches it at (ticketmaster 12h,
+// oddsblaze 30m, when no game is within two weeks) so a quiet stretch isn't reported stale.
+// The injury feed isn't running yet and the schedule only changes when homecourt-init is
+// run, so neither has one.
+var DefaultStaleAfter = map[string]time.Duration{
+	"tickets": 13 * time.Hour,
+	"odds":    45 * time.Minute,
+}
+
+// SourceStatus is how fresh one source's data is. LastUpdated is nil for a source that never
+// delivered anything.
+type SourceStatus struct {
+	Source            string     `json:"source"`
+	LastUpdated       *time.Time `json:"last_updated"`
+	AgeSeconds        *int64     `json:"age_seconds,omitempty"`
+	StaleAfterSeconds int64      `json:"stale_after_seconds,omitempty"`
+	Stale             bool       `json:"stale"`
+}
+
+// Report is the outcome of the readiness checks. Checks maps each check to "ok" or what's
+// wrong with it.
+type Report struct {
+	Ready   bool              `json:"ready"`
+	Checks  map[string]string `json:"checks"`
+	Sources []SourceStatus    `json:"sources,omitempty"`
+}
+
+// Checker decides whether the API is fit to serve traffic.
+type Checker struct {
+	client *redis.Client
+	// StaleAfter is how old a source's data may get before Status reports it stale. Sources
+	// without a threshold are reported but never stale.
+	StaleAfter map[string]time.Duration
+	// Consuming reports whether the receiver is consuming from RabbitMQ. Nil skips the check.
+	Consuming func() bool
+}
+
+func NewChecker(addr string) (*Checker, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return &Checker{client: client, StaleAfter: DefaultStaleAfter}, nil
+}
+
+// RecordUpdate notes that source just delivered data that was stored.
+func (c *Checker) RecordUpdate(ctx context.Context, source string) error {
+	err := c.client.HSet(ctx, SourcesKey, source, time.Now().UTC().Format(time.RFC3339)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to record update of %s: %v", source, err)
+	}
+	return nil
+}
+
+// Sources returns the freshness of every source, in the order of Sources.
+func (c *Checker) Sources(ctx context.Context) ([]SourceStatus, error) {
+	lastUpdated, err := c.client.HGetAll(ctx, SourcesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source updates: %v", err)
+	}
+
+	now := time.Now()
+	statuses := make([]SourceStatus, len(Sources))
+	for i, source := range Sources {
+		status := SourceStatus{Source: source}
+		staleAfter, checked := c.StaleAfter[source]
+		if checked {
+			status.StaleAfterSeconds = int64(staleAfter.Seconds())
+		}
+
+		updated, err := time.Parse(time.RFC3339, lastUpdated[source])
+		if err != nil {
+			// nothing was ever stored from it, which is as stale as it gets
+			status.Stale = checked
+			statuses[i] = status
+			continue
+		}
+		age := int64(now.Sub(updated).Seconds())
+		status.LastUpdated = &updated
+		status.AgeSeconds = &age
+		status.Stale = checked && now.Sub(updated) > staleAfter
+		statuses[i] = status
+	}
+	return statuses, nil
+}
+
+// Check runs the readiness checks: Redis answers and the receiver is consuming. Stale data
+// doesn't make the API unready, there's nothing to deliver in the off-season or before
+// homecourt-init first ran, and taking the API out of rotation wouldn't bring any.
+func (c *Checker) Check(ctx context.Context) Report {
+	report := Report{Ready: true, Checks: map[string]string{}}
+	fail := func(check, detail string) {
+		report.Ready = false
+		report.Checks[check] = detail
+	}
+
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		fail("redis", "ping failed")
+	} else {
+		report.Checks["redis"] = "ok"
+	}
+
+	if c.Consuming != nil {
+		if c.Consuming() {
+			report.Checks["amqp"] = "ok"
+		} else {
+			fail("amqp", "receiver is not consuming")
+		}
+	}
+	return report
+}
+
+// Status is Check plus the freshness of every source, and a freshness check naming the
+// stale ones. Sources is nil if the freshness couldn't be read.
+func (c *Checker) Status(ctx context.Context) Report {
+	report := c.Check(ctx)
+
+	sources, err := c.Sources(ctx)
+	if err != nil {
+		report.Checks["freshness"] = "source updates unavailable"
+		return report
+	}
+	report.Sources = sources
+	var stale []string
+	for _, source := range sources {
+		if source.Stale {
+			stale = append(stale, source.Source)
+		}
+	}
+	if len(stale) > 0 {
+		report.Checks["freshness"] = fmt.Sprintf("stale: %s", strings.Join(stale, ", "))
+	} else {
+		report.Checks["freshness"] = "ok"
+	}
+	return report
+}
+
+// ParseStaleAfter reads thresholds like "tickets=15m,odds=5m". Sources that aren't mentioned
+// keep their default, 0 turns a source's check off.
+func ParseStaleAfter(s string) (map[string]time.Duration, error) {
+	return games.ParseStaleAfter(s, DefaultStaleAfter)
+}
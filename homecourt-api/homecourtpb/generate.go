@@ -0,0 +1,4 @@
+// Package homecourtpb holds the code generated from proto/homecourt.proto.
+package homecourtpb
+
+//go:generate protoc -I ../proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative homecourt.proto
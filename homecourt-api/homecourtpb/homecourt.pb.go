@@ -0,0 +1,1169 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: homecourt.proto
+
+package homecourtpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListGamesRequest_Sort int32
+
+const (
+	ListGamesRequest_SORT_TIP_OFF ListGamesRequest_Sort = 0
+	ListGamesRequest_SORT_PRICE   ListGamesRequest_Sort = 1
+	ListGamesRequest_SORT_ODDS    ListGamesRequest_Sort = 2
+)
+
+// Enum value maps for ListGamesRequest_Sort.
+var (
+	ListGamesRequest_Sort_name = map[int32]string{
+		0: "SORT_TIP_OFF",
+		1: "SORT_PRICE",
+		2: "SORT_ODDS",
+	}
+	ListGamesRequest_Sort_value = map[string]int32{
+		"SORT_TIP_OFF": 0,
+		"SORT_PRICE":   1,
+		"SORT_ODDS":    2,
+	}
+)
+
+func (x ListGamesRequest_Sort) Enum() *ListGamesRequest_Sort {
+	p := new(ListGamesRequest_Sort)
+	*p = x
+	return p
+}
+
+func (x ListGamesRequest_Sort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListGamesRequest_Sort) Descriptor() protoreflect.EnumDescriptor {
+	return file_homecourt_proto_enumTypes[0].Descriptor()
+}
+
+func (ListGamesRequest_Sort) Type() protoreflect.EnumType {
+	return &file_homecourt_proto_enumTypes[0]
+}
+
+func (x ListGamesRequest_Sort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListGamesRequest_Sort.Descriptor instead.
+func (ListGamesRequest_Sort) EnumDescriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type ListGamesRequest_HomeLine int32
+
+const (
+	ListGamesRequest_HOME_LINE_ANY      ListGamesRequest_HomeLine = 0
+	ListGamesRequest_HOME_LINE_FAVORITE ListGamesRequest_HomeLine = 1
+	ListGamesRequest_HOME_LINE_UNDERDOG ListGamesRequest_HomeLine = 2
+)
+
+// Enum value maps for ListGamesRequest_HomeLine.
+var (
+	ListGamesRequest_HomeLine_name = map[int32]string{
+		0: "HOME_LINE_ANY",
+		1: "HOME_LINE_FAVORITE",
+		2: "HOME_LINE_UNDERDOG",
+	}
+	ListGamesRequest_HomeLine_value = map[string]int32{
+		"HOME_LINE_ANY":      0,
+		"HOME_LINE_FAVORITE": 1,
+		"HOME_LINE_UNDERDOG": 2,
+	}
+)
+
+func (x ListGamesRequest_HomeLine) Enum() *ListGamesRequest_HomeLine {
+	p := new(ListGamesRequest_HomeLine)
+	*p = x
+	return p
+}
+
+func (x ListGamesRequest_HomeLine) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListGamesRequest_HomeLine) Descriptor() protoreflect.EnumDescriptor {
+	return file_homecourt_proto_enumTypes[1].Descriptor()
+}
+
+func (ListGamesRequest_HomeLine) Type() protoreflect.EnumType {
+	return &file_homecourt_proto_enumTypes[1]
+}
+
+func (x ListGamesRequest_HomeLine) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListGamesRequest_HomeLine.Descriptor instead.
+func (ListGamesRequest_HomeLine) EnumDescriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{5, 1}
+}
+
+type Team struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Abbreviation string `protobuf:"bytes,1,opt,name=abbreviation,proto3" json:"abbreviation,omitempty"`
+	Name         string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	City         string `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	// IANA timezone of the team's arena.
+	Timezone string `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_homecourt_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Team) GetAbbreviation() string {
+	if x != nil {
+		return x.Abbreviation
+	}
+	return ""
+}
+
+func (x *Team) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Team) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Team) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type Injury struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Team       string `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	PlayerName string `protobuf:"bytes,2,opt,name=player_name,json=playerName,proto3" json:"player_name,omitempty"`
+	Status     string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *Injury) Reset() {
+	*x = Injury{}
+	mi := &file_homecourt_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Injury) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Injury) ProtoMessage() {}
+
+func (x *Injury) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Injury.ProtoReflect.Descriptor instead.
+func (*Injury) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Injury) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+func (x *Injury) GetPlayerName() string {
+	if x != nil {
+		return x.PlayerName
+	}
+	return ""
+}
+
+func (x *Injury) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type Game struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// e.g. "NYK DAL 11.28.2024"
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	HomeTeam  string                 `protobuf:"bytes,2,opt,name=home_team,json=homeTeam,proto3" json:"home_team,omitempty"`
+	AwayTeam  string                 `protobuf:"bytes,3,opt,name=away_team,json=awayTeam,proto3" json:"away_team,omitempty"`
+	Venue     string                 `protobuf:"bytes,4,opt,name=venue,proto3" json:"venue,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	Week      int32                  `protobuf:"varint,6,opt,name=week,proto3" json:"week,omitempty"`
+	Hashtag   string                 `protobuf:"bytes,7,opt,name=hashtag,proto3" json:"hashtag,omitempty"`
+	// scheduled, live, final or postponed, empty until the results feed has seen the game.
+	Status            string   `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	HomeScore         int32    `protobuf:"varint,9,opt,name=home_score,json=homeScore,proto3" json:"home_score,omitempty"`
+	AwayScore         int32    `protobuf:"varint,10,opt,name=away_score,json=awayScore,proto3" json:"away_score,omitempty"`
+	Winner            string   `protobuf:"bytes,11,opt,name=winner,proto3" json:"winner,omitempty"`
+	LowestTicketPrice *float64 `protobuf:"fixed64,12,opt,name=lowest_ticket_price,json=lowestTicketPrice,proto3,oneof" json:"lowest_ticket_price,omitempty"`
+	// Home team's American moneyline, e.g. "+135".
+	HomeTeamOdds       string    `protobuf:"bytes,13,opt,name=home_team_odds,json=homeTeamOdds,proto3" json:"home_team_odds,omitempty"`
+	HomeWinProbability *float64  `protobuf:"fixed64,14,opt,name=home_win_probability,json=homeWinProbability,proto3,oneof" json:"home_win_probability,omitempty"`
+	Injuries           []*Injury `protobuf:"bytes,15,rep,name=injuries,proto3" json:"injuries,omitempty"`
+	// Set for finished games read from the archive.
+	Archived bool `protobuf:"varint,16,opt,name=archived,proto3" json:"archived,omitempty"`
+}
+
+func (x *Game) Reset() {
+	*x = Game{}
+	mi := &file_homecourt_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Game) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Game) ProtoMessage() {}
+
+func (x *Game) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Game.ProtoReflect.Descriptor instead.
+func (*Game) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Game) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Game) GetHomeTeam() string {
+	if x != nil {
+		return x.HomeTeam
+	}
+	return ""
+}
+
+func (x *Game) GetAwayTeam() string {
+	if x != nil {
+		return x.AwayTeam
+	}
+	return ""
+}
+
+func (x *Game) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *Game) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Game) GetWeek() int32 {
+	if x != nil {
+		return x.Week
+	}
+	return 0
+}
+
+func (x *Game) GetHashtag() string {
+	if x != nil {
+		return x.Hashtag
+	}
+	return ""
+}
+
+func (x *Game) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Game) GetHomeScore() int32 {
+	if x != nil {
+		return x.HomeScore
+	}
+	return 0
+}
+
+func (x *Game) GetAwayScore() int32 {
+	if x != nil {
+		return x.AwayScore
+	}
+	return 0
+}
+
+func (x *Game) GetWinner() string {
+	if x != nil {
+		return x.Winner
+	}
+	return ""
+}
+
+func (x *Game) GetLowestTicketPrice() float64 {
+	if x != nil && x.LowestTicketPrice != nil {
+		return *x.LowestTicketPrice
+	}
+	return 0
+}
+
+func (x *Game) GetHomeTeamOdds() string {
+	if x != nil {
+		return x.HomeTeamOdds
+	}
+	return ""
+}
+
+func (x *Game) GetHomeWinProbability() float64 {
+	if x != nil && x.HomeWinProbability != nil {
+		return *x.HomeWinProbability
+	}
+	return 0
+}
+
+func (x *Game) GetInjuries() []*Injury {
+	if x != nil {
+		return x.Injuries
+	}
+	return nil
+}
+
+func (x *Game) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+type ListTeamsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListTeamsRequest) Reset() {
+	*x = ListTeamsRequest{}
+	mi := &file_homecourt_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeamsRequest) ProtoMessage() {}
+
+func (x *ListTeamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeamsRequest.ProtoReflect.Descriptor instead.
+func (*ListTeamsRequest) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{3}
+}
+
+type ListTeamsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Teams []*Team `protobuf:"bytes,1,rep,name=teams,proto3" json:"teams,omitempty"`
+}
+
+func (x *ListTeamsResponse) Reset() {
+	*x = ListTeamsResponse{}
+	mi := &file_homecourt_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeamsResponse) ProtoMessage() {}
+
+func (x *ListTeamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeamsResponse.ProtoReflect.Descriptor instead.
+func (*ListTeamsResponse) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTeamsResponse) GetTeams() []*Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type ListGamesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Defaults to now.
+	From *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Either side of the game.
+	Team  string `protobuf:"bytes,3,opt,name=team,proto3" json:"team,omitempty"`
+	Venue string `protobuf:"bytes,4,opt,name=venue,proto3" json:"venue,omitempty"`
+	// In dollars, 0 for any price. Negative prices are rejected.
+	MaxPrice float64                   `protobuf:"fixed64,5,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	HomeLine ListGamesRequest_HomeLine `protobuf:"varint,6,opt,name=home_line,json=homeLine,proto3,enum=homecourt.v1.ListGamesRequest_HomeLine" json:"home_line,omitempty"`
+	// Only false is accepted until injury reports are stored.
+	HasInjuries *bool                 `protobuf:"varint,7,opt,name=has_injuries,json=hasInjuries,proto3,oneof" json:"has_injuries,omitempty"`
+	Sort        ListGamesRequest_Sort `protobuf:"varint,8,opt,name=sort,proto3,enum=homecourt.v1.ListGamesRequest_Sort" json:"sort,omitempty"`
+	Descending  bool                  `protobuf:"varint,9,opt,name=descending,proto3" json:"descending,omitempty"`
+	// Defaults to 20, at most 100.
+	PageSize  int32  `protobuf:"varint,10,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,11,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ListGamesRequest) Reset() {
+	*x = ListGamesRequest{}
+	mi := &file_homecourt_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesRequest) ProtoMessage() {}
+
+func (x *ListGamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesRequest.ProtoReflect.Descriptor instead.
+func (*ListGamesRequest) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListGamesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListGamesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListGamesRequest) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+func (x *ListGamesRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *ListGamesRequest) GetMaxPrice() float64 {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ListGamesRequest) GetHomeLine() ListGamesRequest_HomeLine {
+	if x != nil {
+		return x.HomeLine
+	}
+	return ListGamesRequest_HOME_LINE_ANY
+}
+
+func (x *ListGamesRequest) GetHasInjuries() bool {
+	if x != nil && x.HasInjuries != nil {
+		return *x.HasInjuries
+	}
+	return false
+}
+
+func (x *ListGamesRequest) GetSort() ListGamesRequest_Sort {
+	if x != nil {
+		return x.Sort
+	}
+	return ListGamesRequest_SORT_TIP_OFF
+}
+
+func (x *ListGamesRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *ListGamesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListGamesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type GameError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId string `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Error  string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *GameError) Reset() {
+	*x = GameError{}
+	mi := &file_homecourt_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameError) ProtoMessage() {}
+
+func (x *GameError) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameError.ProtoReflect.Descriptor instead.
+func (*GameError) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GameError) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *GameError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListGamesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Games         []*Game `protobuf:"bytes,1,rep,name=games,proto3" json:"games,omitempty"`
+	NextPageToken string  `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Games that couldn't be read, the rest are still returned.
+	Errors []*GameError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *ListGamesResponse) Reset() {
+	*x = ListGamesResponse{}
+	mi := &file_homecourt_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesResponse) ProtoMessage() {}
+
+func (x *ListGamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesResponse.ProtoReflect.Descriptor instead.
+func (*ListGamesResponse) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListGamesResponse) GetGames() []*Game {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+func (x *ListGamesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListGamesResponse) GetErrors() []*GameError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type GetGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetGameRequest) Reset() {
+	*x = GetGameRequest{}
+	mi := &file_homecourt_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGameRequest) ProtoMessage() {}
+
+func (x *GetGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGameRequest.ProtoReflect.Descriptor instead.
+func (*GetGameRequest) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetGameRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WatchGamesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Team abbreviations, empty for every game.
+	Teams []string `protobuf:"bytes,1,rep,name=teams,proto3" json:"teams,omitempty"`
+}
+
+func (x *WatchGamesRequest) Reset() {
+	*x = WatchGamesRequest{}
+	mi := &file_homecourt_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchGamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchGamesRequest) ProtoMessage() {}
+
+func (x *WatchGamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchGamesRequest.ProtoReflect.Descriptor instead.
+func (*WatchGamesRequest) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchGamesRequest) GetTeams() []string {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type GameEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// e.g. "game.price_changed"
+	Type      string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	GameId    string                 `protobuf:"bytes,2,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	HomeTeam  string                 `protobuf:"bytes,3,opt,name=home_team,json=homeTeam,proto3" json:"home_team,omitempty"`
+	AwayTeam  string                 `protobuf:"bytes,4,opt,name=away_team,json=awayTeam,proto3" json:"away_team,omitempty"`
+	Changes   map[string]string      `protobuf:"bytes,5,rep,name=changes,proto3" json:"changes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *GameEvent) Reset() {
+	*x = GameEvent{}
+	mi := &file_homecourt_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameEvent) ProtoMessage() {}
+
+func (x *GameEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_homecourt_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameEvent.ProtoReflect.Descriptor instead.
+func (*GameEvent) Descriptor() ([]byte, []int) {
+	return file_homecourt_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GameEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GameEvent) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *GameEvent) GetHomeTeam() string {
+	if x != nil {
+		return x.HomeTeam
+	}
+	return ""
+}
+
+func (x *GameEvent) GetAwayTeam() string {
+	if x != nil {
+		return x.AwayTeam
+	}
+	return ""
+}
+
+func (x *GameEvent) GetChanges() map[string]string {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *GameEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_homecourt_proto protoreflect.FileDescriptor
+
+var file_homecourt_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0c, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0x6e, 0x0a, 0x04, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x62, 0x62, 0x72,
+	0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x61, 0x62, 0x62, 0x72, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65,
+	0x22, 0x55, 0x0a, 0x06, 0x49, 0x6e, 0x6a, 0x75, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x12, 0x1f,
+	0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xce, 0x04, 0x0a, 0x04, 0x47, 0x61, 0x6d, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x1b, 0x0a,
+	0x09, 0x61, 0x77, 0x61, 0x79, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x61, 0x77, 0x61, 0x79, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x65,
+	0x6e, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x65, 0x6e, 0x75, 0x65,
+	0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x77,
+	0x65, 0x65, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x77, 0x65, 0x65, 0x6b, 0x12,
+	0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x68, 0x74, 0x61, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x68, 0x61, 0x73, 0x68, 0x74, 0x61, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x68, 0x6f, 0x6d, 0x65, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x77, 0x61, 0x79, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x61, 0x77, 0x61, 0x79, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x13, 0x6c, 0x6f, 0x77, 0x65, 0x73,
+	0x74, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x11, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x54, 0x69,
+	0x63, 0x6b, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0e,
+	0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x6f, 0x64, 0x64, 0x73, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x6f, 0x6d, 0x65, 0x54, 0x65, 0x61, 0x6d, 0x4f, 0x64,
+	0x64, 0x73, 0x12, 0x35, 0x0a, 0x14, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x5f, 0x70,
+	0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x01, 0x52, 0x12, 0x68, 0x6f, 0x6d, 0x65, 0x57, 0x69, 0x6e, 0x50, 0x72, 0x6f, 0x62, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x08, 0x69, 0x6e, 0x6a,
+	0x75, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x6f,
+	0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x6a, 0x75, 0x72,
+	0x79, 0x52, 0x08, 0x69, 0x6e, 0x6a, 0x75, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x61,
+	0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61,
+	0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x6c, 0x6f, 0x77, 0x65,
+	0x73, 0x74, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x42,
+	0x17, 0x0a, 0x15, 0x5f, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x5f, 0x70, 0x72, 0x6f,
+	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x65, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3d, 0x0a, 0x11,
+	0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x28, 0x0a, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x65, 0x61, 0x6d, 0x52, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x22, 0xd1, 0x04, 0x0a, 0x10,
+	0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d,
+	0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x65, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x65, 0x6e, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x65, 0x6e, 0x75, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x72,
+	0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x12, 0x44, 0x0a, 0x09, 0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x6c, 0x69, 0x6e, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75,
+	0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x48, 0x6f, 0x6d, 0x65, 0x4c, 0x69, 0x6e, 0x65, 0x52,
+	0x08, 0x68, 0x6f, 0x6d, 0x65, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x26, 0x0a, 0x0c, 0x68, 0x61, 0x73,
+	0x5f, 0x69, 0x6e, 0x6a, 0x75, 0x72, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x00, 0x52, 0x0b, 0x68, 0x61, 0x73, 0x49, 0x6e, 0x6a, 0x75, 0x72, 0x69, 0x65, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x37, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x23, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e,
+	0x53, 0x6f, 0x72, 0x74, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65,
+	0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61,
+	0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70,
+	0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67,
+	0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x37, 0x0a, 0x04, 0x53, 0x6f, 0x72, 0x74, 0x12, 0x10,
+	0x0a, 0x0c, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x54, 0x49, 0x50, 0x5f, 0x4f, 0x46, 0x46, 0x10, 0x00,
+	0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x50, 0x52, 0x49, 0x43, 0x45, 0x10, 0x01,
+	0x12, 0x0d, 0x0a, 0x09, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x4f, 0x44, 0x44, 0x53, 0x10, 0x02, 0x22,
+	0x4d, 0x0a, 0x08, 0x48, 0x6f, 0x6d, 0x65, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x48,
+	0x4f, 0x4d, 0x45, 0x5f, 0x4c, 0x49, 0x4e, 0x45, 0x5f, 0x41, 0x4e, 0x59, 0x10, 0x00, 0x12, 0x16,
+	0x0a, 0x12, 0x48, 0x4f, 0x4d, 0x45, 0x5f, 0x4c, 0x49, 0x4e, 0x45, 0x5f, 0x46, 0x41, 0x56, 0x4f,
+	0x52, 0x49, 0x54, 0x45, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x48, 0x4f, 0x4d, 0x45, 0x5f, 0x4c,
+	0x49, 0x4e, 0x45, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x52, 0x44, 0x4f, 0x47, 0x10, 0x02, 0x42, 0x0f,
+	0x0a, 0x0d, 0x5f, 0x68, 0x61, 0x73, 0x5f, 0x69, 0x6e, 0x6a, 0x75, 0x72, 0x69, 0x65, 0x73, 0x22,
+	0x3a, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07,
+	0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67,
+	0x61, 0x6d, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x96, 0x01, 0x0a, 0x11,
+	0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x28, 0x0a, 0x05, 0x67, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x61, 0x6d, 0x65, 0x52, 0x05, 0x67, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x12, 0x2f, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x22, 0x20, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x29, 0x0a, 0x11, 0x57, 0x61, 0x74, 0x63, 0x68, 0x47,
+	0x61, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x65, 0x61, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x65, 0x61, 0x6d,
+	0x73, 0x22, 0xa8, 0x02, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09,
+	0x68, 0x6f, 0x6d, 0x65, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x68, 0x6f, 0x6d, 0x65, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x77, 0x61,
+	0x79, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x77,
+	0x61, 0x79, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x3e, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f,
+	0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x63,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x1a, 0x3a, 0x0a, 0x0c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x32, 0xae, 0x02, 0x0a,
+	0x09, 0x48, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x12, 0x4c, 0x0a, 0x09, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x73, 0x12, 0x1e, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f,
+	0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f,
+	0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74,
+	0x47, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x47, 0x61, 0x6d,
+	0x65, 0x12, 0x1c, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x12, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x61, 0x6d, 0x65, 0x12, 0x48, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x47, 0x61, 0x6d, 0x65,
+	0x73, 0x12, 0x1f, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x17, 0x2e, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x1b, 0x5a,
+	0x19, 0x68, 0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x68,
+	0x6f, 0x6d, 0x65, 0x63, 0x6f, 0x75, 0x72, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_homecourt_proto_rawDescOnce sync.Once
+	file_homecourt_proto_rawDescData = file_homecourt_proto_rawDesc
+)
+
+func file_homecourt_proto_rawDescGZIP() []byte {
+	file_homecourt_proto_rawDescOnce.Do(func() {
+		file_homecourt_proto_rawDescData = protoimpl.X.CompressGZIP(file_homecourt_proto_rawDescData)
+	})
+	return file_homecourt_proto_rawDescData
+}
+
+var file_homecourt_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_homecourt_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_homecourt_proto_goTypes = []any{
+	(ListGamesRequest_Sort)(0),     // 0: homecourt.v1.ListGamesRequest.Sort
+	(ListGamesRequest_HomeLine)(0), // 1: homecourt.v1.ListGamesRequest.HomeLine
+	(*Team)(nil),                   // 2: homecourt.v1.Team
+	(*Injury)(nil),                 // 3: homecourt.v1.Injury
+	(*Game)(nil),                   // 4: homecourt.v1.Game
+	(*ListTeamsRequest)(nil),       // 5: homecourt.v1.ListTeamsRequest
+	(*ListTeamsResponse)(nil),      // 6: homecourt.v1.ListTeamsResponse
+	(*ListGamesRequest)(nil),       // 7: homecourt.v1.ListGamesRequest
+	(*GameError)(nil),              // 8: homecourt.v1.GameError
+	(*ListGamesResponse)(nil),      // 9: homecourt.v1.ListGamesResponse
+	(*GetGameRequest)(nil),         // 10: homecourt.v1.GetGameRequest
+	(*WatchGamesRequest)(nil),      // 11: homecourt.v1.WatchGamesRequest
+	(*GameEvent)(nil),              // 12: homecourt.v1.GameEvent
+	nil,                            // 13: homecourt.v1.GameEvent.ChangesEntry
+	(*timestamppb.Timestamp)(nil),  // 14: google.protobuf.Timestamp
+}
+var file_homecourt_proto_depIdxs = []int32{
+	14, // 0: homecourt.v1.Game.start_time:type_name -> google.protobuf.Timestamp
+	3,  // 1: homecourt.v1.Game.injuries:type_name -> homecourt.v1.Injury
+	2,  // 2: homecourt.v1.ListTeamsResponse.teams:type_name -> homecourt.v1.Team
+	14, // 3: homecourt.v1.ListGamesRequest.from:type_name -> google.protobuf.Timestamp
+	14, // 4: homecourt.v1.ListGamesRequest.to:type_name -> google.protobuf.Timestamp
+	1,  // 5: homecourt.v1.ListGamesRequest.home_line:type_name -> homecourt.v1.ListGamesRequest.HomeLine
+	0,  // 6: homecourt.v1.ListGamesRequest.sort:type_name -> homecourt.v1.ListGamesRequest.Sort
+	4,  // 7: homecourt.v1.ListGamesResponse.games:type_name -> homecourt.v1.Game
+	8,  // 8: homecourt.v1.ListGamesResponse.errors:type_name -> homecourt.v1.GameError
+	13, // 9: homecourt.v1.GameEvent.changes:type_name -> homecourt.v1.GameEvent.ChangesEntry
+	14, // 10: homecourt.v1.GameEvent.timestamp:type_name -> google.protobuf.Timestamp
+	5,  // 11: homecourt.v1.Homecourt.ListTeams:input_type -> homecourt.v1.ListTeamsRequest
+	7,  // 12: homecourt.v1.Homecourt.ListGames:input_type -> homecourt.v1.ListGamesRequest
+	10, // 13: homecourt.v1.Homecourt.GetGame:input_type -> homecourt.v1.GetGameRequest
+	11, // 14: homecourt.v1.Homecourt.WatchGames:input_type -> homecourt.v1.WatchGamesRequest
+	6,  // 15: homecourt.v1.Homecourt.ListTeams:output_type -> homecourt.v1.ListTeamsResponse
+	9,  // 16: homecourt.v1.Homecourt.ListGames:output_type -> homecourt.v1.ListGamesResponse
+	4,  // 17: homecourt.v1.Homecourt.GetGame:output_type -> homecourt.v1.Game
+	12, // 18: homecourt.v1.Homecourt.WatchGames:output_type -> homecourt.v1.GameEvent
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_homecourt_proto_init() }
+func file_homecourt_proto_init() {
+	if File_homecourt_proto != nil {
+		return
+	}
+	file_homecourt_proto_msgTypes[2].OneofWrappers = []any{}
+	file_homecourt_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_homecourt_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_homecourt_proto_goTypes,
+		DependencyIndexes: file_homecourt_proto_depIdxs,
+		EnumInfos:         file_homecourt_proto_enumTypes,
+		MessageInfos:      file_homecourt_proto_msgTypes,
+	}.Build()
+	File_homecourt_proto = out.File
+	file_homecourt_proto_rawDesc = nil
+	file_homecourt_proto_goTypes = nil
+	file_homecourt_proto_depIdxs = nil
+}
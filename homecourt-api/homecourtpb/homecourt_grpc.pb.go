@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: homecourt.proto
+
+package homecourtpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Homecourt_ListTeams_FullMethodName  = "/homecourt.v1.Homecourt/ListTeams"
+	Homecourt_ListGames_FullMethodName  = "/homecourt.v1.Homecourt/ListGames"
+	Homecourt_GetGame_FullMethodName    = "/homecourt.v1.Homecourt/GetGame"
+	Homecourt_WatchGames_FullMethodName = "/homecourt.v1.Homecourt/WatchGames"
+)
+
+// HomecourtClient is the client API for Homecourt service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Homecourt serves the same game data as the HTTP API, for other services.
+type HomecourtClient interface {
+	ListTeams(ctx context.Context, in *ListTeamsRequest, opts ...grpc.CallOption) (*ListTeamsResponse, error)
+	// ListGames searches upcoming games, with the same filters as GET /v1/games.
+	ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error)
+	// GetGame looks up a game by ID, falling back to the archive for finished games.
+	GetGame(ctx context.Context, in *GetGameRequest, opts ...grpc.CallOption) (*Game, error)
+	// WatchGames streams game changes as they happen, optionally only for some teams.
+	WatchGames(ctx context.Context, in *WatchGamesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GameEvent], error)
+}
+
+type homecourtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHomecourtClient(cc grpc.ClientConnInterface) HomecourtClient {
+	return &homecourtClient{cc}
+}
+
+func (c *homecourtClient) ListTeams(ctx context.Context, in *ListTeamsRequest, opts ...grpc.CallOption) (*ListTeamsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTeamsResponse)
+	err := c.cc.Invoke(ctx, Homecourt_ListTeams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *homecourtClient) ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGamesResponse)
+	err := c.cc.Invoke(ctx, Homecourt_ListGames_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *homecourtClient) GetGame(ctx context.Context, in *GetGameRequest, opts ...grpc.CallOption) (*Game, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Game)
+	err := c.cc.Invoke(ctx, Homecourt_GetGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *homecourtClient) WatchGames(ctx context.Context, in *WatchGamesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GameEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Homecourt_ServiceDesc.Streams[0], Homecourt_WatchGames_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchGamesRequest, GameEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Homecourt_WatchGamesClient = grpc.ServerStreamingClient[GameEvent]
+
+// HomecourtServer is the server API for Homecourt service.
+// All implementations must embed UnimplementedHomecourtServer
+// for forward compatibility.
+//
+// Homecourt serves the same game data as the HTTP API, for other services.
+type HomecourtServer interface {
+	ListTeams(context.Context, *ListTeamsRequest) (*ListTeamsResponse, error)
+	// ListGames searches upcoming games, with the same filters as GET /v1/games.
+	ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error)
+	// GetGame looks up a game by ID, falling back to the archive for finished games.
+	GetGame(context.Context, *GetGameRequest) (*Game, error)
+	// WatchGames streams game changes as they happen, optionally only for some teams.
+	WatchGames(*WatchGamesRequest, grpc.ServerStreamingServer[GameEvent]) error
+	mustEmbedUnimplementedHomecourtServer()
+}
+
+// UnimplementedHomecourtServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHomecourtServer struct{}
+
+func (UnimplementedHomecourtServer) ListTeams(context.Context, *ListTeamsRequest) (*ListTeamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTeams not implemented")
+}
+func (UnimplementedHomecourtServer) ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGames not implemented")
+}
+func (UnimplementedHomecourtServer) GetGame(context.Context, *GetGameRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGame not implemented")
+}
+func (UnimplementedHomecourtServer) WatchGames(*WatchGamesRequest, grpc.ServerStreamingServer[GameEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchGames not implemented")
+}
+func (UnimplementedHomecourtServer) mustEmbedUnimplementedHomecourtServer() {}
+func (UnimplementedHomecourtServer) testEmbeddedByValue()                   {}
+
+// UnsafeHomecourtServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HomecourtServer will
+// result in compilation errors.
+type UnsafeHomecourtServer interface {
+	mustEmbedUnimplementedHomecourtServer()
+}
+
+func RegisterHomecourtServer(s grpc.ServiceRegistrar, srv HomecourtServer) {
+	// If the following call pancis, it indicates UnimplementedHomecourtServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Homecourt_ServiceDesc, srv)
+}
+
+func _Homecourt_ListTeams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTeamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HomecourtServer).ListTeams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Homecourt_ListTeams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HomecourtServer).ListTeams(ctx, req.(*ListTeamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Homecourt_ListGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HomecourtServer).ListGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Homecourt_ListGames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HomecourtServer).ListGames(ctx, req.(*ListGamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Homecourt_GetGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HomecourtServer).GetGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Homecourt_GetGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HomecourtServer).GetGame(ctx, req.(*GetGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Homecourt_WatchGames_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchGamesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HomecourtServer).WatchGames(m, &grpc.GenericServerStream[WatchGamesRequest, GameEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Homecourt_WatchGamesServer = grpc.ServerStreamingServer[GameEvent]
+
+// Homecourt_ServiceDesc is the grpc.ServiceDesc for Homecourt service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Homecourt_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "homecourt.v1.Homecourt",
+	HandlerType: (*HomecourtServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTeams",
+			Handler:    _Homecourt_ListTeams_Handler,
+		},
+		{
+			MethodName: "ListGames",
+			Handler:    _Homecourt_ListGames_Handler,
+		},
+		{
+			MethodName: "GetGame",
+			Handler:    _Homecourt_GetGame_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchGames",
+			Handler:       _Homecourt_WatchGames_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "homecourt.proto",
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/joho/godotenv"
@@ -56,7 +56,7 @@ func main() {
 
 	err := godotenv.Load("../.env.local")
 	if err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
+		fatal("error loading .env file", "err", err)
 	}
 
 	// Retrieve database connection details from environment variables
@@ -68,7 +68,7 @@ func main() {
 	dbSSLMode := os.Getenv("DB_SSLMODE") // e.g., "disable", "require"
 
 	if dbHost == "" || dbPort == "" || dbUser == "" || dbPassword == "" || dbName == "" || dbSSLMode == "" {
-		log.Fatal("One or more required environment variables are missing (DB_HOST, DB_PORT, DB_USER, DB_PASS, DB_NAME, DB_SSLMODE)")
+		fatal("one or more required environment variables are missing (DB_HOST, DB_PORT, DB_USER, DB_PASS, DB_NAME, DB_SSLMODE)")
 	}
 
 	// Construct the connection string
@@ -78,16 +78,16 @@ func main() {
 	// Connect to the PostgreSQL database
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
-		log.Fatalf("Failed to open a DB connection: %v", err)
+		fatal("failed to open a DB connection", "err", err)
 	}
 	defer db.Close()
 
 	// Verify the connection
 	err = db.Ping()
 	if err != nil {
-		log.Fatalf("Failed to connect to DB: %v", err)
+		fatal("failed to connect to DB", "err", err)
 	}
-	log.Println("Successfully connected to the database.")
+	slog.Info("connected to the database")
 
 	// Create a context
 	ctx := context.Background()
@@ -95,7 +95,7 @@ func main() {
 	// Execute table creation in a transaction
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		log.Fatalf("Failed to begin transaction: %v", err)
+		fatal("failed to begin transaction", "err", err)
 	}
 
 	// List of table creation queries in the correct order
@@ -110,16 +110,22 @@ func main() {
 		_, err := tx.ExecContext(ctx, query)
 		if err != nil {
 			tx.Rollback()
-			log.Fatalf("Failed to execute query: %v\nError: %v", query, err)
+			fatal("failed to execute query", "query", query, "err", err)
 		}
-		log.Println("Executed query successfully.")
+		slog.Debug("executed query", "query", query)
 	}
 
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
-		log.Fatalf("Failed to commit transaction: %v", err)
+		fatal("failed to commit transaction", "err", err)
 	}
 
-	log.Println("Database schema initialized successfully.")
+	slog.Info("database schema initialized")
+}
+
+// fatal logs msg and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
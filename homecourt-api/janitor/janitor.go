@@ -0,0 +1,88 @@
+package janitor
+
+import (
+	"context"
+	"homecourt-api/games"
+	"homecourt-api/teams"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// how often past games are swept out of the upcoming indexes
+	sweepInterval = 10 * time.Minute
+	// how long archived games (and their final odds and prices) are kept around
+	archiveTTL = 30 * 24 * time.Hour
+)
+
+var Manager games.GamesManager
+
+var (
+	sweeps = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "homecourt_janitor_sweeps_total",
+		Help: "Sweeps of every team's upcoming games.",
+	})
+	sweepErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "homecourt_janitor_sweep_errors_total",
+		Help: "Teams a sweep failed to archive or reindex.",
+	})
+	gamesArchived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "homecourt_janitor_games_archived_total",
+		Help: "Finished games moved to the archive, by home team.",
+	}, []string{"team"})
+	lastSweep = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "homecourt_janitor_last_sweep_timestamp_seconds",
+		Help: "When the last sweep finished, as a Unix time.",
+	})
+)
+
+// Janitor archives finished games and refreshes the search indexes every sweepInterval
+// until ctx is cancelled.
+func Janitor(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			slog.Info("janitor has been stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func sweep(ctx context.Context) {
+	finishedBefore := time.Now().Add(-games.GameLength)
+	total := 0
+
+	for teamID := range teams.Registry {
+		archived, err := Manager.ArchivePastGames(ctx, teamID, finishedBefore, archiveTTL)
+		// ArchivePastGames returns what it managed to archive even when it fails part way
+		total += len(archived)
+		gamesArchived.WithLabelValues(teamID).Add(float64(len(archived)))
+		if err != nil {
+			sweepErrors.Inc()
+			slog.Error("error archiving past games", "team", teamID, "err", err)
+			continue
+		}
+
+		// repairs whatever a failed write left out of the indexes
+		_, err = Manager.ReindexGames(ctx, teamID)
+		if err != nil {
+			sweepErrors.Inc()
+			slog.Error("error indexing upcoming games", "team", teamID, "err", err)
+		}
+	}
+
+	sweeps.Inc()
+	lastSweep.SetToCurrentTime()
+	if total > 0 {
+		slog.Info("archived finished games", "count", total)
+	}
+}
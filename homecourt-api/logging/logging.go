@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"homecourt-api/correlation"
+	"homecourt-api/requestid"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Setup makes slog's default logger write JSON to stdout, or text when format is "text",
+// at level (debug, info, warn or error; info when empty). The log package writes through
+// it too.
+func Setup(level, format string) error {
+	var logLevel slog.Level
+	if level != "" {
+		err := logLevel.UnmarshalText([]byte(level))
+		if err != nil {
+			return fmt.Errorf("invalid log level %q: %v", level, err)
+		}
+	}
+
+	options := &slog.HandlerOptions{Level: logLevel}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(os.Stdout, options)
+	case "text":
+		handler = slog.NewTextHandler(os.Stdout, options)
+	default:
+		return fmt.Errorf("invalid log format %q, expected json or text", format)
+	}
+
+	slog.SetDefault(slog.New(ContextHandler{handler}))
+	return nil
+}
+
+// ContextHandler adds the request, correlation and trace IDs of the context passed to slog's
+// ...Context functions to every record.
+type ContextHandler struct {
+	slog.Handler
+}
+
+func (h ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if id := correlation.FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("correlation_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return ContextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h ContextHandler) WithGroup(name string) slog.Handler {
+	return ContextHandler{h.Handler.WithGroup(name)}
+}
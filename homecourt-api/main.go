@@ -2,55 +2,181 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/smtp"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // arena timezones must resolve in the alpine image
 
+	"homecourt-api/alerts"
+	"homecourt-api/apikeys"
+	"homecourt-api/events"
 	"homecourt-api/games"
+	"homecourt-api/graph"
 	"homecourt-api/handlers"
+	"homecourt-api/health"
+	"homecourt-api/janitor"
+	"homecourt-api/logging"
+	"homecourt-api/middleware"
+	"homecourt-api/openapi"
+	"homecourt-api/ratelimit"
 	"homecourt-api/receiver"
+	"homecourt-api/recommendations"
+	"homecourt-api/requestid"
+	"homecourt-api/rpc"
+	"homecourt-api/tracing"
+	"homecourt-api/users"
+	"homecourt-api/webhooks"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// enableCORS sets the necessary CORS headers and handles preflight requests.
-func enableCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*") // Allow all origins (adjust as needed)
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-		// Handle preflight OPTIONS request
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		// Call the next handler
-		next.ServeHTTP(w, r)
-	})
-}
-
 func main() {
 	// Load environment variables from .env.local
 	err := godotenv.Load(".env.local")
 	if err != nil {
-		log.Fatalf("Error loading .env.local: %v", err)
+		fatal("error loading .env.local", "err", err)
+	}
+
+	// LOG_LEVEL is debug, info (default), warn or error. LOG_FORMAT=text is easier to read locally.
+	err = logging.Setup(os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		fatal("invalid logging configuration", "err", err)
+	}
+
+	// OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4318 sends traces to a local collector
+	shutdownTracing, err := tracing.Setup(context.Background(), "homecourt-api")
+	if err != nil {
+		fatal("invalid tracing configuration", "err", err)
 	}
 
 	// Initialize GamesManager with Redis
 	gamesManager, err := games.NewGamesManager("localhost:6379")
 	if err != nil {
-		log.Fatalf("Could not connect to Redis: %v", err)
+		fatal("could not connect to Redis", "err", err)
 	}
 
-	// Assign the GamesManager to receiver and handlers
+	// Initialize the event hub, also backed by Redis so every replica sees every change
+	hub, err := events.NewHub("localhost:6379")
+	if err != nil {
+		fatal("could not connect to Redis", "err", err)
+	}
+
+	// Initialize alert rules and the notifiers they can deliver through
+	rulesManager, err := alerts.NewRulesManager("localhost:6379")
+	if err != nil {
+		fatal("could not connect to Redis", "err", err)
+	}
+	evaluator := &alerts.Evaluator{
+		Rules: rulesManager,
+		Notifiers: map[string]alerts.Notifier{
+			"log":     alerts.LogNotifier{},
+			"webhook": alerts.NewWebhookNotifier(),
+		},
+	}
+	if smtpAddr := os.Getenv("SMTP_ADDR"); smtpAddr != "" {
+		notifier := &alerts.SMTPNotifier{Addr: smtpAddr, From: os.Getenv("SMTP_FROM")}
+		if user := os.Getenv("SMTP_USER"); user != "" {
+			host, _, _ := net.SplitHostPort(smtpAddr)
+			notifier.Auth = smtp.PlainAuth("", user, os.Getenv("SMTP_PASS"), host)
+		}
+		evaluator.Notifiers["smtp"] = notifier
+	}
+
+	// Initialize partner webhooks
+	webhooksManager, err := webhooks.NewWebhooksManager("localhost:6379")
+	if err != nil {
+		fatal("could not connect to Redis", "err", err)
+	}
+
+	// Initialize user accounts and sessions
+	usersManager, err := users.NewUsersManager("localhost:6379")
+	if err != nil {
+		fatal("could not connect to Redis", "err", err)
+	}
+
+	// Initialize API keys and the rate limiter, both in Redis so limits hold across replicas
+	keysManager, err := apikeys.NewKeysManager("localhost:6379")
+	if err != nil {
+		fatal("could not connect to Redis", "err", err)
+	}
+	limiter, err := ratelimit.NewLimiter("localhost:6379")
+	if err != nil {
+		fatal("could not connect to Redis", "err", err)
+	}
+
+	// Readiness and data freshness. FRESHNESS_THRESHOLDS overrides how old a source's data
+	// may get before /v1/status reports it stale, e.g. tickets=3h,odds=10m,schedule=168h
+	// during the season, when no team is more than a week from its next game
+	healthChecker, err := health.NewChecker("localhost:6379")
+	if err != nil {
+		fatal("could not connect to Redis", "err", err)
+	}
+	if s := os.Getenv("FRESHNESS_THRESHOLDS"); s != "" {
+		healthChecker.StaleAfter, err = health.ParseStaleAfter(s)
+		if err != nil {
+			fatal("invalid FRESHNESS_THRESHOLDS", "err", err)
+		}
+	}
+	healthChecker.Consuming = receiver.Consuming
+
+	// GAMES_CACHE_SIZE keeps that many recently read games in memory for the read paths.
+	// The receiver and janitor compare against and write Redis, so they always go direct.
+	// Every call is traced either way, cached reads just have no Redis spans under them.
+	var readManager games.GamesManager = games.NewTracedGamesManager(gamesManager)
+	var gamesCache *games.CachedGamesManager
+	if size, err := strconv.Atoi(os.Getenv("GAMES_CACHE_SIZE")); err == nil && size > 0 {
+		gamesCache, err = games.NewCachedGamesManager(gamesManager, "localhost:6379", size)
+		if err != nil {
+			fatal("could not connect to Redis", "err", err)
+		}
+		readManager = games.NewTracedGamesManager(gamesCache)
+	}
+	gamesManager = games.NewTracedGamesManager(gamesManager)
+
+	// Assign the GamesManager to receiver, handlers, graph, rpc and janitor
 	receiver.Manager = gamesManager
-	handlers.Manager = gamesManager
+	handlers.Manager = readManager
+	graph.Manager = readManager
+	rpc.Manager = readManager
+	janitor.Manager = gamesManager
+	receiver.Events = hub
+	handlers.Events = hub
+	rpc.Events = hub
+	receiver.Alerts = evaluator
+	handlers.Alerts = evaluator
+	receiver.Webhooks = webhooks.NewDispatcher(webhooksManager)
+	receiver.Health = healthChecker
+	handlers.Health = healthChecker
+	handlers.Webhooks = webhooksManager
+	handlers.Users = usersManager
+	handlers.APIKeys = keysManager
+	handlers.AdminToken = os.Getenv("ADMIN_TOKEN")
+
+	// e.g. RECOMMENDATION_WEIGHTS=price=0.6,competitiveness=0.3,stars=0.1
+	if s := os.Getenv("RECOMMENDATION_WEIGHTS"); s != "" {
+		weights, err := recommendations.ParseWeights(s)
+		if err != nil {
+			fatal("invalid RECOMMENDATION_WEIGHTS", "err", err)
+		}
+		handlers.RecommendationWeights = weights
+	}
+
+	// Game fields older than this are flagged <group>_stale, e.g. GAME_STALE_AFTER=tickets=2h,odds=15m
+	if s := os.Getenv("GAME_STALE_AFTER"); s != "" {
+		staleAfter, err := games.ParseStaleAfter(s, games.DefaultStaleAfter)
+		if err != nil {
+			fatal("invalid GAME_STALE_AFTER", "err", err)
+		}
+		handlers.StaleAfter = staleAfter
+	}
 
 	// Create a cancellable context
 	ctx, cancel := context.WithCancel(context.Background())
@@ -59,24 +185,151 @@ func main() {
 	// Start the receiver in a separate goroutine
 	go receiver.Receiver(ctx)
 
+	// Archive finished games in the background
+	go janitor.Janitor(ctx)
+
+	// Relay game events from Redis to stream subscribers
+	go hub.Run(ctx)
+
+	// Drop cached games as any replica changes them
+	if gamesCache != nil {
+		go gamesCache.Run(ctx)
+	}
+
 	// Create a new ServeMux and register handlers
 	mux := http.NewServeMux()
-	mux.HandleFunc("/get", handlers.GetHandler)
+	mux.HandleFunc("GET /healthz", handlers.HealthzHandler)
+	mux.HandleFunc("GET /readyz", handlers.ReadyzHandler)
+	mux.HandleFunc("GET /v1/status", handlers.StatusHandler)
+	mux.HandleFunc("POST /get", handlers.GetHandler)
+	mux.HandleFunc("GET /v1/teams/{abbr}/results", handlers.ResultsHandler)
+	mux.HandleFunc("GET /v1/teams/{abbr}/recommendations", handlers.RecommendationsHandler)
+	mux.HandleFunc("GET /v1/games", handlers.GamesHandler)
+	mux.HandleFunc("GET /v1/games/{id}", handlers.GameHandler)
+	mux.HandleFunc("GET /v1/stream", handlers.StreamHandler)
+	mux.Handle("POST /v1/graphql", graph.Handler())
+	mux.HandleFunc("POST /v1/alerts", handlers.RequireUser(handlers.CreateAlertHandler))
+	mux.HandleFunc("GET /v1/alerts", handlers.RequireUser(handlers.ListAlertsHandler))
+	mux.HandleFunc("GET /v1/alerts/{id}", handlers.RequireUser(handlers.GetAlertHandler))
+	mux.HandleFunc("DELETE /v1/alerts/{id}", handlers.RequireUser(handlers.DeleteAlertHandler))
+	mux.HandleFunc("POST /v1/webhooks", handlers.RequireAdmin(handlers.CreateWebhookHandler))
+	mux.HandleFunc("GET /v1/webhooks", handlers.RequireAdmin(handlers.ListWebhooksHandler))
+	mux.HandleFunc("GET /v1/webhooks/{id}", handlers.RequireAdmin(handlers.GetWebhookHandler))
+	mux.HandleFunc("DELETE /v1/webhooks/{id}", handlers.RequireAdmin(handlers.DeleteWebhookHandler))
+	mux.HandleFunc("GET /v1/webhooks/{id}/deliveries", handlers.RequireAdmin(handlers.WebhookDeliveriesHandler))
+	mux.HandleFunc("POST /v1/signup", handlers.SignupHandler)
+	mux.HandleFunc("POST /v1/login", handlers.LoginHandler)
+	mux.HandleFunc("POST /v1/logout", handlers.RequireUser(handlers.LogoutHandler))
+	mux.HandleFunc("GET /v1/me", handlers.RequireUser(handlers.MeHandler))
+	mux.HandleFunc("PUT /v1/me/favorites", handlers.RequireUser(handlers.FavoritesHandler))
+	mux.HandleFunc("GET /v1/me/games", handlers.RequireUser(handlers.MyGamesHandler))
+	mux.HandleFunc("POST /v1/apikeys", handlers.RequireAdmin(handlers.CreateKeyHandler))
+	mux.HandleFunc("GET /v1/apikeys", handlers.RequireAdmin(handlers.ListKeysHandler))
+	mux.HandleFunc("DELETE /v1/apikeys/{id}", handlers.RequireAdmin(handlers.RevokeKeyHandler))
+	mux.HandleFunc("GET /v1/apikeys/{id}/usage", handlers.RequireAdmin(handlers.KeyUsageHandler))
+	mux.Handle("GET /metrics", promhttp.Handler())
 
-	// Wrap the mux with CORS middleware
-	handlerWithCORS := enableCORS(mux)
+	// The API contract, checked against live traffic when OPENAPI_VALIDATION is
+	// "report" (log mismatches) or "strict" (fail them)
+	spec, err := openapi.Load()
+	if err != nil {
+		fatal("could not load OpenAPI spec", "err", err)
+	}
+	specHandler, err := openapi.Handler(spec)
+	if err != nil {
+		fatal("could not serve OpenAPI spec", "err", err)
+	}
+	mux.Handle("GET /openapi.json", specHandler)
+	validator, err := openapi.NewValidator(spec, os.Getenv("OPENAPI_VALIDATION"))
+	if err != nil {
+		fatal("invalid OPENAPI_VALIDATION", "err", err)
+	}
+
+	// Verify API keys and rate limit by IP, and by key as well for requests with one.
+	// Set REQUIRE_API_KEY=true to turn away requests without a key. Probes don't have one.
+	// Behind a load balancer, set TRUSTED_PROXIES to its addresses (e.g. 10.0.0.0/8) so
+	// clients are told apart by X-Forwarded-For rather than all limited as the proxy.
+	trustedProxies, err := middleware.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		fatal("invalid TRUSTED_PROXIES", "err", err)
+	}
+	apiKeys := &middleware.APIKeys{
+		Keys:           keysManager,
+		Limiter:        limiter,
+		Mux:            mux,
+		Require:        os.Getenv("REQUIRE_API_KEY") == "true",
+		Anonymous:      ratelimit.Limit{PerMinute: 60, Burst: 20},
+		PerIP:          ratelimit.Limit{PerMinute: 600, Burst: 100},
+		TrustedProxies: trustedProxies,
+		Public:         []string{"GET /healthz", "GET /readyz", "GET /metrics"},
+	}
+
+	// Answer CORS outside the api key check so preflights don't need a key.
+	// CORS_ALLOWED_ORIGINS is a comma separated list of origins, "*" allows any.
+	allowedOrigins := []string{"http://localhost:3000"}
+	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		allowedOrigins = strings.Split(origins, ",")
+		for i := range allowedOrigins {
+			allowedOrigins[i] = strings.TrimSpace(allowedOrigins[i])
+		}
+	}
+	maxAge := 10 * time.Minute
+	if seconds, err := strconv.Atoi(os.Getenv("CORS_MAX_AGE")); err == nil {
+		maxAge = time.Duration(seconds) * time.Second
+	}
+	cors := &middleware.CORS{
+		Mux:              mux,
+		AllowedOrigins:   allowedOrigins,
+		AllowCredentials: os.Getenv("CORS_ALLOW_CREDENTIALS") == "true",
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-API-Key", "X-Admin-Token", "If-None-Match", requestid.Header},
+		ExposedHeaders:   []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After", "ETag", requestid.Header},
+		MaxAge:           maxAge,
+	}
+	if err := cors.Validate(); err != nil {
+		fatal("invalid CORS configuration", "err", err)
+	}
+	handlerWithCORS := cors.Handler(apiKeys.Handler(validator.Handler(mux)))
+
+	// Time every request, including the ones turned away before they reach a handler
+	metrics := &middleware.Metrics{Mux: mux}
+
+	// Start a span for every request, continuing the caller's trace if it sent one
+	tracer := &middleware.Tracing{Mux: mux}
+
+	// Tag every request with an ID first, so even rejected ones can be traced
+	handler := requestid.Handler(tracer.Handler(metrics.Handler(handlerWithCORS)))
 
 	// Initialize the HTTP server with the wrapped handler
 	server := &http.Server{
 		Addr:    ":8080",
-		Handler: handlerWithCORS,
+		Handler: handler,
 	}
+	// open streams never go idle, so end them or Shutdown waits out its whole timeout
+	server.RegisterOnShutdown(hub.Close)
 
 	// Start the server in a separate goroutine
 	go func() {
-		log.Println("Starting HTTP server on :8080")
+		slog.Info("starting HTTP server", "addr", server.Addr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("HTTP server failed: %v", err)
+			fatal("HTTP server failed", "err", err)
+		}
+	}()
+
+	// Serve the same data over gRPC for other services
+	grpcAddr := os.Getenv("GRPC_ADDR")
+	if grpcAddr == "" {
+		grpcAddr = ":9090"
+	}
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		fatal("could not listen", "addr", grpcAddr, "err", err)
+	}
+	// with the same API keys and limits as HTTP, keys are sent as x-api-key metadata
+	grpcServer := rpc.NewServer(apiKeys)
+	go func() {
+		slog.Info("starting gRPC server", "addr", grpcAddr)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			fatal("gRPC server failed", "err", err)
 		}
 	}()
 
@@ -85,7 +338,7 @@ func main() {
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	<-sigs
 
-	log.Println("Shutdown signal received")
+	slog.Info("shutdown signal received")
 
 	// Create a context with timeout for the shutdown
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -93,13 +346,38 @@ func main() {
 
 	// Attempt to gracefully shutdown the server
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Printf("HTTP server Shutdown: %v", err)
+		slog.Error("HTTP server shutdown", "err", err)
 	} else {
-		log.Println("HTTP server gracefully stopped")
+		slog.Info("HTTP server gracefully stopped")
+	}
+
+	// WatchGames streams already ended with the hub, so this only waits on unary calls
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+		slog.Info("gRPC server gracefully stopped")
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+		slog.Info("gRPC server stopped")
 	}
 
 	// Cancel the main context to stop the Receiver
 	cancel()
 
-	log.Println("Server shutdown complete")
+	// Export the spans that are still buffered
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("tracing shutdown", "err", err)
+	}
+
+	slog.Info("server shutdown complete")
+}
+
+// fatal logs a startup failure and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"homecourt-api/apikeys"
+	"homecourt-api/problem"
+	"homecourt-api/ratelimit"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeys verifies API keys, rate limits every request with a token bucket per client IP and
+// another per key for requests with one, and records usage per key.
+//
+// Keys are read from the X-API-Key header, or the api_key query parameter for clients like
+// EventSource that can't set headers.
+type APIKeys struct {
+	Keys    apikeys.KeysManager
+	Limiter *ratelimit.Limiter
+	// Mux resolves requests to the route pattern usage is recorded under
+	Mux *http.ServeMux
+	// Require rejects requests without a key instead of limiting them by IP
+	Require bool
+	// Anonymous is the per-IP limit for requests without a key
+	Anonymous ratelimit.Limit
+	// PerIP is the per-IP limit for requests with a key, on top of the key's own limit, so a
+	// leaked or shared key can't be spent by a single client. Zero leaves only the key's limit.
+	PerIP ratelimit.Limit
+	// TrustedProxies are the proxies whose X-Forwarded-For is believed. Without any the
+	// client IP is the connection's remote address.
+	TrustedProxies []*net.IPNet
+	// Public route patterns are served without checking keys or limits, e.g. probes
+	Public []string
+}
+
+// statusRecorder remembers the status code a handler wrote.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Flush keeps server-sent event streams working through the recorder.
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func (m *APIKeys) clientIP(r *http.Request) string {
+	return m.ClientIP(r.RemoteAddr, r.Header.Values("X-Forwarded-For"))
+}
+
+// ClientIP is the address of whoever sent a request that came from remoteAddr. When that's
+// a trusted proxy, it's the right-most X-Forwarded-For entry no trusted proxy added; entries
+// further left were written by the client and could be anything.
+func (m *APIKeys) ClientIP(remoteAddr string, forwardedFor []string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	if !m.trusted(host) {
+		return host
+	}
+
+	forwarded := strings.Split(strings.Join(forwardedFor, ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(addr) == nil {
+			// not a hop we can vouch for, so neither are the ones before it
+			break
+		}
+		host = addr
+		if !m.trusted(addr) {
+			break
+		}
+	}
+	return host
+}
+
+func (m *APIKeys) trusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range m.TrustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies reads a comma separated list of CIDRs or single addresses, e.g.
+// "10.0.0.0/8,192.168.1.7".
+func ParseTrustedProxies(s string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid proxy address: %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			entry = fmt.Sprintf("%s/%d", entry, bits)
+		}
+		_, proxy, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy range: %q", entry)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+// bucket is a token bucket a request is counted against.
+type bucket struct {
+	name  string
+	limit ratelimit.Limit
+}
+
+func (m *APIKeys) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(m.Public) > 0 {
+			if _, route := m.Mux.Handler(r); slices.Contains(m.Public, route) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		secret := r.Header.Get("X-API-Key")
+		if secret == "" {
+			secret = r.URL.Query().Get("api_key")
+		}
+
+		decision, err := m.Check(r.Context(), secret, m.clientIP(r))
+		switch {
+		case errors.Is(err, ErrInvalidKey), errors.Is(err, ErrMissingKey):
+			problem.Write(w, r, http.StatusUnauthorized, problem.Unauthorized, err.Error())
+			return
+		case err != nil:
+			problem.Write(w, r, http.StatusInternalServerError, problem.Internal, "failed to verify api key")
+			return
+		}
+		key, result := decision.Key, decision.Result
+		if decision.Limited {
+			limit := decision.Limit
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(result.Reset))
+			w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=60;burst=%d", limit.PerMinute, limit.Burst))
+			if !result.Allowed {
+				w.Header().Set("Retry-After", seconds(result.RetryAfter))
+			}
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		if decision.Limited && !result.Allowed {
+			problem.Write(recorder, r, http.StatusTooManyRequests, problem.RateLimited, "rate limit exceeded")
+		} else {
+			next.ServeHTTP(recorder, r)
+		}
+
+		if key.ID != "" {
+			_, route := m.Mux.Handler(r)
+			if route == "" {
+				route = "unmatched"
+			}
+			m.RecordUsage(r.Context(), key, route, recorder.status)
+		}
+	})
+}
+
+// Decision is what Check made of a request.
+type Decision struct {
+	Key apikeys.Key // zero for requests without one
+	// Limited is false when the rate limit couldn't be checked, the request goes through
+	Limited bool
+	Result  ratelimit.Result // of the bucket that binds
+	Limit   ratelimit.Limit
+}
+
+var (
+	ErrInvalidKey = errors.New("invalid api key")
+	ErrMissingKey = errors.New("missing api key")
+)
+
+// Check verifies secret, which may be empty, and counts a request from ip against the buckets
+// that apply to it. It's the part of Handler that isn't HTTP, for the gRPC server to share.
+func (m *APIKeys) Check(ctx context.Context, secret, ip string) (Decision, error) {
+	var decision Decision
+	buckets := []bucket{{fmt.Sprintf("ip:%s", ip), m.Anonymous}}
+	if secret != "" {
+		key, err := m.Keys.GetKeyBySecret(ctx, secret)
+		if err == apikeys.ErrKeyNotFound {
+			return decision, ErrInvalidKey
+		}
+		if err != nil {
+			return decision, err
+		}
+		decision.Key = key
+		buckets = []bucket{{fmt.Sprintf("key:%s", key.ID), key.Limit}}
+		if m.PerIP.PerMinute > 0 {
+			// separate from the anonymous bucket, keyed clients are allowed more
+			buckets = append(buckets, bucket{fmt.Sprintf("key-ip:%s", ip), m.PerIP})
+		}
+	} else if m.Require {
+		return decision, ErrMissingKey
+	}
+
+	result, limit, err := m.allow(ctx, buckets)
+	if err != nil {
+		// better to serve unthrottled than not at all while Redis is struggling
+		slog.WarnContext(ctx, "error checking rate limit, letting request through", "err", err)
+		return decision, nil
+	}
+	decision.Limited, decision.Result, decision.Limit = true, result, limit
+	return decision, nil
+}
+
+// RecordUsage counts a request made with key against route, logging failures.
+func (m *APIKeys) RecordUsage(ctx context.Context, key apikeys.Key, route string, status int) {
+	// the request context may already be cancelled (e.g. a closed stream)
+	usageCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+	defer cancel()
+	if err := m.Keys.RecordUsage(usageCtx, key.ID, route, status); err != nil {
+		slog.ErrorContext(ctx, "error recording api key usage", "key_id", key.ID, "err", err)
+	}
+}
+
+// allow counts a request against every bucket and returns the result of the one that binds:
+// the one that turned it away for longest, or, when all allowed it, the one with the fewest
+// requests left.
+func (m *APIKeys) allow(ctx context.Context, buckets []bucket) (ratelimit.Result, ratelimit.Limit, error) {
+	var binding ratelimit.Result
+	var limit ratelimit.Limit
+	for i, b := range buckets {
+		result, err := m.Limiter.Allow(ctx, b.name, b.limit)
+		if err != nil {
+			return ratelimit.Result{}, ratelimit.Limit{}, err
+		}
+		switch {
+		case i == 0,
+			binding.Allowed && !result.Allowed,
+			!binding.Allowed && !result.Allowed && result.RetryAfter > binding.RetryAfter,
+			binding.Allowed && result.Allowed && result.Remaining < binding.Remaining:
+			binding, limit = result, b.limit
+		}
+	}
+	return binding, limit, nil
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// methods probed against the mux to work out what a route accepts
+var corsMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// CORS answers preflight requests and adds CORS headers for allowed origins.
+// The methods allowed for a path are the ones Mux has routes for.
+type CORS struct {
+	Mux *http.ServeMux
+	// AllowedOrigins are exact origins like "https://homecourt.example.com", or "*" for any
+	AllowedOrigins []string
+	// AllowCredentials lets browsers send cookies and Authorization headers. It can't be
+	// combined with "*", that would let any site make requests as the user, see Validate.
+	AllowCredentials bool
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+// Validate rejects configurations that would be unsafe to serve.
+func (c *CORS) Validate() error {
+	if c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		return errors.New(`credentials can't be allowed for any origin ("*"), list the origins instead`)
+	}
+	return nil
+}
+
+func (c *CORS) originAllowed(origin string) bool {
+	return slices.Contains(c.AllowedOrigins, "*") || slices.Contains(c.AllowedOrigins, origin)
+}
+
+// routeMethods returns the methods the mux has a route for at r's path.
+func (c *CORS) routeMethods(r *http.Request) []string {
+	var methods []string
+	for _, method := range corsMethods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := c.Mux.Handler(probe); pattern != "" {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		// responses differ by origin, caches have to know that even when it isn't allowed
+		w.Header().Add("Vary", "Origin")
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" || !c.originAllowed(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			// not a cross-origin request we serve, the browser will block the response
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !slices.Contains(c.AllowedOrigins, "*") {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+		if c.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if len(c.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		methods := c.routeMethods(r)
+		if len(methods) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		if !slices.Contains(methods, r.Header.Get("Access-Control-Request-Method")) {
+			w.Header().Set("Allow", strings.Join(append(methods, http.MethodOptions), ", "))
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+		if len(c.AllowedHeaders) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(c.AllowedHeaders, ", "))
+		}
+		if c.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "homecourt_http_request_duration_seconds",
+	Help:    "HTTP requests, by route and status code. Streams are timed until the client goes away.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"route", "code"})
+
+// Metrics records how long every request took, by route pattern and status code.
+type Metrics struct {
+	// Mux resolves requests to the route pattern they're recorded under
+	Mux *http.ServeMux
+}
+
+func (m *Metrics) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		// raw paths would give every game and alert ID its own series
+		_, route := m.Mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		httpDuration.WithLabelValues(route, strconv.Itoa(recorder.status)).Observe(time.Since(start).Seconds())
+	})
+}
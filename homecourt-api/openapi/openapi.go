@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"homecourt-api/problem"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// Spec is the contract for every homecourt-api endpoint. Update it with the handlers.
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+// Validation modes, set with OPENAPI_VALIDATION.
+const (
+	// Off doesn't validate anything, for production.
+	Off = ""
+	// Report logs requests and responses that don't match the spec, for development.
+	Report = "report"
+	// Strict rejects requests that don't match the spec and turns responses that don't into
+	// 500s, so tests and CI fail on drift instead of shipping it.
+	Strict = "strict"
+)
+
+// Load parses and validates Spec.
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi spec: %v", err)
+	}
+	err = doc.Validate(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %v", err)
+	}
+	return doc, nil
+}
+
+// Handler serves the spec as JSON, for /openapi.json.
+func Handler(doc *openapi3.T) (http.Handler, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal openapi spec: %v", err)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}), nil
+}
+
+// Validator checks requests and responses against the spec.
+type Validator struct {
+	Mode   string
+	router routers.Router
+}
+
+func NewValidator(doc *openapi3.T, mode string) (*Validator, error) {
+	if mode != Off && mode != Report && mode != Strict {
+		return nil, fmt.Errorf("unknown validation mode %q, expected %q or %q", mode, Report, Strict)
+	}
+	// match paths on whatever host the server is reached through, not just the documented one
+	routed := *doc
+	routed.Servers = nil
+	router, err := legacy.NewRouter(&routed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route openapi spec: %v", err)
+	}
+	return &Validator{Mode: mode, router: router}, nil
+}
+
+// Handler validates what goes in and out of next. Routes missing from the spec are
+// reported but passed through, the mux decides whether they exist.
+func (v *Validator) Handler(next http.Handler) http.Handler {
+	if v.Mode == Off {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			slog.WarnContext(r.Context(), "route is not in the API spec", "method", r.Method, "path", r.URL.Path, "err", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestOptions := &openapi3filter.Options{
+			// the api key and session middlewares check credentials, not the spec
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		}
+		requestOptions.WithCustomSchemaErrorFunc(schemaError)
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    requestOptions,
+		}
+		err = openapi3filter.ValidateRequest(r.Context(), input)
+		if err != nil {
+			slog.WarnContext(r.Context(), "request does not match the API spec", "method", r.Method, "route", route.Path, "err", err)
+			if v.Mode == Strict {
+				problem.Write(w, r, http.StatusBadRequest, problem.InvalidRequest, fmt.Sprintf("request does not match the API spec: %v", err))
+				return
+			}
+		}
+
+		// streams never finish, so there's no complete response to check
+		if streams(route) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &responseRecorder{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		responseOptions := &openapi3filter.Options{IncludeResponseStatus: true}
+		responseOptions.WithCustomSchemaErrorFunc(schemaError)
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 recorder.status,
+			Header:                 recorder.header,
+			Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+			Options:                responseOptions,
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "response does not match the API spec", "method", r.Method, "route", route.Path, "status", recorder.status, "err", err)
+			if v.Mode == Strict {
+				problem.Write(w, r, http.StatusInternalServerError, problem.ContractViolation, fmt.Sprintf("response does not match the API spec: %v", err))
+				return
+			}
+		}
+
+		for key, values := range recorder.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(recorder.status)
+		w.Write(recorder.body.Bytes())
+	})
+}
+
+// schemaError leaves out the schema and value dumps that make errors a screen long, the
+// path to the field is enough. Errors wrapping another one are left to describe it.
+func schemaError(err *openapi3.SchemaError) string {
+	if err.Origin != nil {
+		return ""
+	}
+	reason := err.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("doesn't match schema %q", err.SchemaField)
+	}
+	pointer := err.JSONPointer()
+	if len(pointer) == 0 {
+		return reason
+	}
+	return fmt.Sprintf("Error at %q: %s", "/"+strings.Join(pointer, "/"), reason)
+}
+
+func streams(route *routers.Route) bool {
+	for _, response := range route.Operation.Responses.Map() {
+		if response.Value == nil {
+			continue
+		}
+		for contentType := range response.Value.Content {
+			if strings.HasPrefix(contentType, "text/event-stream") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// responseRecorder holds a response back until it's been validated.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header         { return r.header }
+func (r *responseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *responseRecorder) WriteHeader(status int)      { r.status = status }
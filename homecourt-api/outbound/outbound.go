@@ -0,0 +1,86 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for URLs that resolve to an address the server must not
+// send requests to on a user's behalf: loopback, private, link-local (which includes cloud
+// metadata endpoints), multicast or unspecified.
+var ErrForbiddenAddress = errors.New("address not allowed")
+
+// carrier-grade NAT space isn't covered by net.IP.IsPrivate but is just as internal
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Allowed reports whether ip is a public unicast address.
+func Allowed(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+// CheckURL validates a URL users asked the server to call: it must be absolute http(s), and
+// every address its host resolves to must be Allowed. The addresses are checked again when
+// the connection is made, see NewClient, because DNS can change in between.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL")
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !Allowed(ip) {
+			return fmt.Errorf("%s: %w", host, ErrForbiddenAddress)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("can't resolve %s", host)
+	}
+	for _, addr := range addrs {
+		if !Allowed(addr.IP) {
+			return fmt.Errorf("%s: %w", host, ErrForbiddenAddress)
+		}
+	}
+	return nil
+}
+
+// NewClient returns a client that refuses to connect to addresses that aren't Allowed,
+// whatever the URL's host resolved to at dial time. Redirects are dialed the same way.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !Allowed(ip) {
+				return fmt.Errorf("%s: %w", host, ErrForbiddenAddress)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// a proxy would be dialed instead of the target, and it's the target that matters
+	transport.Proxy = nil
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
@@ -0,0 +1,85 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"homecourt-api/games"
+	"homecourt-api/requestid"
+	"log/slog"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+// Codes are stable, clients can branch on them. Detail is for people and may change.
+const (
+	InvalidRequest    = "invalid_request"
+	InvalidTeam       = "invalid_team"
+	NotFound          = "not_found"
+	GameNotFound      = "game_not_found"
+	Unauthorized      = "unauthorized"
+	Forbidden         = "forbidden"
+	Conflict          = "conflict"
+	RateLimited       = "rate_limited"
+	Unavailable       = "unavailable"
+	Internal          = "internal"
+	ContractViolation = "contract_violation"
+)
+
+// Problem is an RFC 7807 problem details object, with the error code and request ID as
+// extension members.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Type is the problem type URI for code.
+func Type(code string) string {
+	return "urn:homecourt:problem:" + code
+}
+
+// Write responds with a problem. detail must not contain internals like Redis keys.
+func Write(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Problem{
+		Type:      Type(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: requestid.FromContext(r.Context()),
+	})
+}
+
+// Classify maps an error from the GamesManager onto a status and code.
+func Classify(err error) (int, string) {
+	switch {
+	case errors.Is(err, games.ErrGameNotFound):
+		return http.StatusNotFound, GameNotFound
+	case errors.Is(err, games.ErrInvalidTeam):
+		return http.StatusBadRequest, InvalidTeam
+	case errors.Is(err, games.ErrUnavailable), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable, Unavailable
+	default:
+		return http.StatusInternalServerError, Internal
+	}
+}
+
+// Error responds with the problem err classifies as. The error itself is only logged,
+// detail is what the client sees.
+func Error(w http.ResponseWriter, r *http.Request, err error, detail string) {
+	status, code := Classify(err)
+	if status >= http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), detail, "method", r.Method, "path", r.URL.Path, "err", err)
+	}
+	Write(w, r, status, code, detail)
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"homecourt-api/metrics"
+	"homecourt-api/tracing"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limit is a token bucket: Burst requests at once, refilled at PerMinute requests a minute.
+type Limit struct {
+	PerMinute int `json:"per_minute"`
+	Burst     int `json:"burst"`
+}
+
+// Result is the state of a bucket after a request was counted against it.
+type Result struct {
+	Allowed    bool
+	Limit      int           // the bucket's size
+	Remaining  int           // requests left right now
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request is allowed, zero when Allowed
+}
+
+// tokenBucket refills the bucket for the time since it was last touched and takes a token if
+// there is one. Running it as a script keeps replicas from racing on the same bucket.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// Limiter keeps token buckets in Redis so limits hold across replicas.
+type Limiter struct {
+	client *redis.Client
+}
+
+func NewLimiter(addr string) (*Limiter, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return &Limiter{client: client}, nil
+}
+
+// Allow counts a request against the bucket named key.
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	perSecond := float64(limit.PerMinute) / 60
+	now := time.Now().UnixMilli()
+
+	values, err := tokenBucket.Run(ctx, l.client, []string{fmt.Sprintf("ratelimit:%s", key)}, perSecond, limit.Burst, now).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to check rate limit for %s: %v", key, err)
+	}
+	allowed, _ := values[0].(int64)
+	tokensStr, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected rate limit state for %s: %v", key, values)
+	}
+
+	secondsUntil := func(target float64) time.Duration {
+		if tokens >= target {
+			return 0
+		}
+		return time.Duration(math.Ceil((target-tokens)/perSecond)) * time.Second
+	}
+
+	result := Result{
+		Allowed:   allowed == 1,
+		Limit:     limit.Burst,
+		Remaining: int(tokens),
+		Reset:     secondsUntil(float64(limit.Burst)),
+	}
+	if !result.Allowed {
+		result.RetryAfter = secondsUntil(1)
+	}
+	return result, nil
+}
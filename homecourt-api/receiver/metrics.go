@@ -0,0 +1,47 @@
+package receiver
+
+import (
+	"errors"
+	"homecourt-api/games"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// storeData outcomes
+const (
+	outcomeStored      = "stored"
+	outcomeUnknownTeam = "unknown_team"
+	outcomeGameMissing = "game_missing"
+	outcomeOutOfOrder  = "out_of_order"
+	outcomeUnavailable = "redis_unavailable"
+	outcomeInvalid     = "invalid"
+)
+
+var (
+	messagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "homecourt_messages_consumed_total",
+		Help: "Messages consumed, by queue.",
+	}, []string{"queue"})
+	storeOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "homecourt_store_outcomes_total",
+		Help: "What became of consumed messages, by queue and outcome (stored, unknown_team, game_missing, out_of_order, redis_unavailable, invalid).",
+	}, []string{"queue", "outcome"})
+)
+
+func storeOutcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeStored
+	case errors.Is(err, errUnknownTeam):
+		return outcomeUnknownTeam
+	case errors.Is(err, errGameMissing):
+		return outcomeGameMissing
+	case errors.Is(err, games.ErrOutOfOrder):
+		return outcomeOutOfOrder
+	case errors.Is(err, games.ErrUnavailable):
+		return outcomeUnavailable
+	default:
+		return outcomeInvalid
+	}
+}
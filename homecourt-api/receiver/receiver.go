@@ -3,26 +3,51 @@ package receiver
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"homecourt-api/alerts"
+	"homecourt-api/correlation"
+	"homecourt-api/events"
 	"homecourt-api/games"
-	"log"
+	"homecourt-api/health"
+	"homecourt-api/tracing"
+	"homecourt-api/webhooks"
+	"log/slog"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("homecourt-api/receiver")
+
 func failOnError(err error, msg string) {
 	if err != nil {
-		log.Panicf("%s: %s", msg, err)
+		slog.Error(msg, "err", err)
+		panic(fmt.Sprintf("%s: %s", msg, err))
 	}
 }
 
+// consuming is set while the receiver is connected and consuming every queue.
+var consuming atomic.Bool
+
+// Consuming reports whether the receiver is connected to RabbitMQ and consuming every queue.
+// It doesn't reconnect, so once this is false it stays false.
+func Consuming() bool {
+	return consuming.Load()
+}
+
 func Receiver(ctx context.Context) {
 	defer func() {
+		consuming.Store(false)
 		if r := recover(); r != nil {
-			log.Printf("reciever panicked: %v", r)
+			slog.Error("receiver panicked", "panic", r)
 		}
 	}()
 
@@ -30,6 +55,15 @@ func Receiver(ctx context.Context) {
 	failOnError(err, "failed to connect to rabbitmq")
 	defer conn.Close()
 
+	// the consumers' channels just close when the connection drops
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	go func() {
+		if err := <-closed; err != nil {
+			consuming.Store(false)
+			slog.Error("rabbitmq connection closed", "err", err)
+		}
+	}()
+
 	channel, err := conn.Channel()
 	failOnError(err, "failed to open a channel")
 	defer channel.Close()
@@ -44,10 +78,10 @@ func Receiver(ctx context.Context) {
 		nil,                  // arguments
 	)
 	failOnError(err, "failed to declare exchange")
-	log.Printf("homecourt_exchange declared successfully")
+	slog.Info("exchange declared", "exchange", "homecourt_exchange")
 
 	// Declare Queues and Bindings
-	queues := []string{"tickets", "odds", "injuries"}
+	queues := []string{"tickets", "odds", "injuries", "results"}
 	for _, queueName := range queues {
 		_, err := channel.QueueDeclare(
 			queueName, // name
@@ -58,7 +92,7 @@ func Receiver(ctx context.Context) {
 			nil,       // arguments
 		)
 		failOnError(err, fmt.Sprintf("failed to declare queue: %s", queueName))
-		log.Printf("successfully declared queue: %s", queueName)
+		slog.Info("queue declared", "queue", queueName)
 
 		err = channel.QueueBind(
 			queueName,            // queue name
@@ -68,7 +102,7 @@ func Receiver(ctx context.Context) {
 			nil,
 		)
 		failOnError(err, fmt.Sprintf("failed to bind queue %s to homecourt_exchange", queueName))
-		log.Printf("successfully bound queue %s to homecourt_exchange with routing key %s", queueName, queueName)
+		slog.Info("queue bound", "queue", queueName, "exchange", "homecourt_exchange", "routing_key", queueName)
 
 		messages, err := channel.Consume(
 			queueName,
@@ -80,37 +114,135 @@ func Receiver(ctx context.Context) {
 			nil,
 		)
 		failOnError(err, fmt.Sprintf("failed to register consumer %s", queueName))
-		log.Printf("successfully registered consumer for queue: %s", queueName)
+		slog.Info("consumer registered", "queue", queueName)
 
 		go func(queue string, messages <-chan amqp.Delivery) {
 			for d := range messages {
-				log.Printf(" [x] %s", d.Body)
-
-				var parsedData map[string]interface{}
-				err := json.Unmarshal(d.Body, &parsedData)
-				if err != nil {
-					log.Printf("error parsing message from %s queue: %v", queue, err)
-					continue
-				}
-
-				err = storeData(ctx, queue, parsedData)
-				if err != nil {
-					log.Printf("error storing message from %s queue: %v", queue, err)
-					continue
-				}
-
-				// {"event_name":"Atlanta Hawks vs Miami Heat","start_date_time":"2025-02-25T00:30:00Z","min_ticket_price":25,"venue_name":"State Farm Arena"}
-				// {"away_team":"Minnesota Timberwolves","home_team":"Sacramento Kings","start_time":"Saturday, Nov 16, 2024 at 3:00am","betting_prices":{"Minnesota Timberwolves":"-105","Sacramento Kings":"-115"}
-				d.Ack(false)
+				handleMessage(ctx, queue, d)
 			}
 		}(queueName, messages)
 	}
+	consuming.Store(true)
 	<-ctx.Done()
-	log.Println("Receiver has been stopped")
+	slog.Info("receiver has been stopped")
+}
+
+// handleMessage stores one message and acks it. Messages that can't be stored are left
+// unacked.
+func handleMessage(ctx context.Context, queue string, d amqp.Delivery) {
+	// ties every log line and game update below to the provider fetch the message came
+	// from, messages from older producers get an ID of their own
+	id, _ := d.Headers[correlation.Header].(string)
+	if id == "" {
+		id = correlation.New()
+	}
+	ctx = correlation.NewContext(ctx, id)
+
+	// continues the trace the producer started when it fetched from the provider
+	ctx = otel.GetTextMapPropagator().Extract(ctx, tracing.HeaderCarrier(d.Headers))
+	ctx, span := tracer.Start(ctx, queue+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", queue),
+			attribute.Int("messaging.message.body.size", len(d.Body)),
+			attribute.String("correlation_id", id),
+		),
+	)
+	defer span.End()
+
+	slog.DebugContext(ctx, "message received", "queue", queue, "bytes", len(d.Body))
+	messagesConsumed.WithLabelValues(queue).Inc()
+
+	var parsedData map[string]interface{}
+	err := json.Unmarshal(d.Body, &parsedData)
+	if err != nil {
+		storeOutcomes.WithLabelValues(queue, outcomeInvalid).Inc()
+		span.SetStatus(codes.Error, "invalid message")
+		slog.ErrorContext(ctx, "error parsing message", "queue", queue, "err", err)
+		return
+	}
+
+	// the producer stamps messages with the provider and the time it fetched from it
+	observed := observation{source: defaultSources[queue], at: time.Now()}
+	if source, _ := d.Headers[sourceHeader].(string); source != "" {
+		observed.source = source
+	}
+	if !d.Timestamp.IsZero() {
+		observed.at = d.Timestamp
+	}
+
+	storeCtx, storeSpan := tracer.Start(ctx, "storeData")
+	err = storeData(storeCtx, queue, parsedData, observed)
+	outcome := storeOutcome(err)
+	storeSpan.SetAttributes(attribute.String("outcome", outcome))
+	storeOutcomes.WithLabelValues(queue, outcome).Inc()
+	if errors.Is(err, errGameMissing) || errors.Is(err, games.ErrOutOfOrder) {
+		// the feeds cover games homecourt-init hasn't imported, there's nothing to update,
+		// and a redelivered or delayed message has already been superseded
+		slog.DebugContext(ctx, "skipping message", "queue", queue, "reason", err)
+		err = nil
+	}
+	tracing.End(storeSpan, err)
+	if err != nil {
+		span.SetStatus(codes.Error, "message not stored")
+		slog.ErrorContext(ctx, "error storing message", "queue", queue, "err", err)
+		return
+	}
+	if Health != nil && outcome == outcomeStored {
+		if err := Health.RecordUpdate(ctx, queue); err != nil {
+			slog.WarnContext(ctx, "error recording source update", "queue", queue, "err", err)
+		}
+	}
+
+	// {"event_name":"Atlanta Hawks vs Miami Heat","start_date_time":"2025-02-25T00:30:00Z","min_ticket_price":25,"venue_name":"State Farm Arena"}
+	// {"away_team":"Minnesota Timberwolves","home_team":"Sacramento Kings","start_time":"Saturday, Nov 16, 2024 at 3:00am","betting_prices":{"Minnesota Timberwolves":"-105","Sacramento Kings":"-115"}
+	d.Ack(false)
 }
 
 var Manager games.GamesManager
 
+// sourceHeader names the provider a message's data was fetched from.
+const sourceHeader = "source"
+
+// the providers behind each queue, for messages from producers that don't say
+var defaultSources = map[string]string{
+	"tickets":  "ticketmaster",
+	"odds":     "oddsblaze",
+	"injuries": "espn",
+	"results":  "espn",
+}
+
+// observation is where and when the data in a message was fetched. storeData stamps the
+// groups of game fields it writes with it.
+type observation struct {
+	source string
+	at     time.Time
+}
+
+// of is the observation of group's fields.
+func (o observation) of(group string) games.Observation {
+	return games.Observation{Group: group, Source: o.source, At: o.at}
+}
+
+// Reasons storeData didn't store a message, for the outcomes metric.
+var (
+	errUnknownTeam = errors.New("unknown team")
+	errGameMissing = errors.New("game does not exist")
+)
+
+// Events is notified of every change storeData makes to a game. Optional.
+var Events *events.Hub
+
+// Alerts evaluates alert rules against every change storeData makes to a game. Optional.
+var Alerts *alerts.Evaluator
+
+// Webhooks delivers every change storeData makes to a game to subscribed partners. Optional.
+var Webhooks *webhooks.Dispatcher
+
+// Health is told whenever a queue's message was stored, for freshness. Optional.
+var Health *health.Checker
+
 var TeamAbbreviation = map[string]string{
 	"atlanta hawks":          "ATL",
 	"boston celtics":         "BOS",
@@ -146,18 +278,23 @@ var TeamAbbreviation = map[string]string{
 	"washington wizards":     "WAS",
 }
 
-func storeData(ctx context.Context, queue string, data map[string]interface{}) error {
+func storeData(ctx context.Context, queue string, data map[string]interface{}, observed observation) error {
 	switch queue {
 	case "tickets":
 		eventName := data["event_name"].(string)
 		homeTeam, awayTeam, err := extractTeams(eventName)
 		if err != nil {
-			return fmt.Errorf("could not extract home team & away team out of tickets message: %v", err)
+			return fmt.Errorf("could not extract home team & away team out of tickets message: %w: %v", errUnknownTeam, err)
 		}
 
-		date, _ := time.Parse(time.RFC3339, data["start_date_time"].(string))
-		formattedDate := date.Format("01.02.2006")
+		// game IDs are keyed on the UTC date, same as homecourt-init
 		startTime := data["start_date_time"].(string)
+		date, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			return fmt.Errorf("invalid start_date_time %q in tickets message: %v", startTime, err)
+		}
+		date = date.UTC()
+		formattedDate := date.Format("01.02.2006")
 		lowestTicketPrice := fmt.Sprintf("$%.2f", data["min_ticket_price"].(float64))
 
 		gameID := fmt.Sprintf("%s %s %s", TeamAbbreviation[homeTeam], TeamAbbreviation[awayTeam], formattedDate)
@@ -165,24 +302,36 @@ func storeData(ctx context.Context, queue string, data map[string]interface{}) e
 
 		exists, err := Manager.GameExists(ctx, gameKey)
 		if err != nil {
-			return fmt.Errorf("error checking game existence: %v", err)
+			return fmt.Errorf("error checking game existence: %w", err)
 		}
 		if !exists {
-			log.Printf("Game %s does not exist", gameID)
-			return nil
+			return fmt.Errorf("game %s: %w", gameID, errGameMissing)
+		}
+		previous, err := Manager.GetGame(ctx, gameID)
+		if err != nil {
+			return err
 		}
-		log.Print(gameKey)
 		fields := map[string]interface{}{
 			"lowest_ticket_price": lowestTicketPrice,
 		}
-		err = Manager.CreateOrUpdateGame(ctx, gameKey, fields)
+		current, err := updateGame(ctx, events.PriceChanged, gameID, gameKey, observed.of(games.GroupTickets), previous, fields)
 		if err != nil {
 			return err
 		}
 
+		// ticketmaster has the most up to date tip-off, the schedule import can be weeks old
+		tipOff := date.Format(time.RFC3339)
+		if current["start_time"] != "" && current["start_time"] != tipOff {
+			_, err = updateGame(ctx, events.Rescheduled, gameID, gameKey, observed.of(games.GroupSchedule), current, map[string]interface{}{
+				"start_time": tipOff,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		zsetKey := fmt.Sprintf("team:%s:upcoming_home_games", TeamAbbreviation[homeTeam])
-		gameTime, _ := time.Parse(time.RFC3339, startTime)
-		score := gameTime.Unix()
+		score := date.Unix()
 		err = Manager.AddUpcomingGame(ctx, zsetKey, gameID, score)
 		if err != nil {
 			return err
@@ -195,31 +344,27 @@ func storeData(ctx context.Context, queue string, data map[string]interface{}) e
 		// Map team names to abbreviations
 		homeTeamAbbr, ok := TeamAbbreviation[homeTeamName]
 		if !ok {
-			log.Printf("Unknown home team: %s", homeTeamName)
-			return fmt.Errorf("unknown home team")
+			return fmt.Errorf("home team %q: %w", homeTeamName, errUnknownTeam)
 		}
 
 		awayTeamAbbr, ok := TeamAbbreviation[awayTeamName]
 		if !ok {
-			log.Printf("Unknown away team: %s", awayTeamName)
-			return fmt.Errorf("unknown away team")
+			return fmt.Errorf("away team %q: %w", awayTeamName, errUnknownTeam)
 		}
 
 		// Parse the date
 		dateStr := data["start_time"].(string)
 		date, err := time.Parse(time.RFC3339, dateStr)
 		if err != nil {
-			log.Printf("Error parsing date '%s': %v", dateStr, err)
-			return fmt.Errorf("invalid date format")
+			return fmt.Errorf("invalid start_time %q: %v", dateStr, err)
 		}
-		formattedDate := date.Format("01.02.2006")
+		formattedDate := date.UTC().Format("01.02.2006")
 
 		// Extract odds
 		odds := data["betting_prices"].(map[string]interface{})
 		homeTeamOddsStr, ok := odds[data["home_team"].(string)].(string)
 		if !ok {
-			log.Printf("odds not found for home team: %s", data["home_team"].(string))
-			return fmt.Errorf("odds not found for home team")
+			return fmt.Errorf("odds not found for home team %q", data["home_team"].(string))
 		}
 
 		// Construct the game ID and key
@@ -229,28 +374,110 @@ func storeData(ctx context.Context, queue string, data map[string]interface{}) e
 		// Check if the game exists
 		exists, err := Manager.GameExists(ctx, gameKey)
 		if err != nil {
-			return fmt.Errorf("error checking game existence: %v", err)
+			return fmt.Errorf("error checking game existence: %w", err)
 		}
 		if !exists {
-			log.Printf("Game %s does not exist", gameID)
-			return nil
+			return fmt.Errorf("game %s: %w", gameID, errGameMissing)
 		}
 
 		// Update the game with odds
+		previous, err := Manager.GetGame(ctx, gameID)
+		if err != nil {
+			return err
+		}
 		fields := map[string]interface{}{
 			"home_team_odds": homeTeamOddsStr,
 		}
 
-		err = Manager.CreateOrUpdateGame(ctx, gameKey, fields)
+		_, err = updateGame(ctx, events.OddsChanged, gameID, gameKey, observed.of(games.GroupOdds), previous, fields)
 		if err != nil {
-			log.Printf("Failed to update game: %v", err)
 			return err
 		}
-		log.Printf("Odds updated for game %s", gameID)
+		slog.DebugContext(ctx, "odds stored", "game_id", gameID, "home_team_odds", homeTeamOddsStr)
 
 	case "injuries":
 		panic("unimplemented")
 
+	case "results":
+		homeTeamName := strings.ToLower(data["home_team"].(string))
+		awayTeamName := strings.ToLower(data["away_team"].(string))
+
+		homeTeamAbbr, ok := TeamAbbreviation[homeTeamName]
+		if !ok {
+			return fmt.Errorf("home team %q: %w", homeTeamName, errUnknownTeam)
+		}
+
+		awayTeamAbbr, ok := TeamAbbreviation[awayTeamName]
+		if !ok {
+			return fmt.Errorf("away team %q: %w", awayTeamName, errUnknownTeam)
+		}
+
+		dateStr := data["start_time"].(string)
+		date, err := time.Parse(time.RFC3339, dateStr)
+		if err != nil {
+			return fmt.Errorf("invalid start_time %q: %v", dateStr, err)
+		}
+		formattedDate := date.UTC().Format("01.02.2006")
+
+		status := data["status"].(string)
+		homeScore := int(data["home_score"].(float64))
+		awayScore := int(data["away_score"].(float64))
+
+		gameID := fmt.Sprintf("%s %s %s", homeTeamAbbr, awayTeamAbbr, formattedDate)
+
+		// finals usually land after the janitor has archived the game
+		gameKey := fmt.Sprintf("game:%s", gameID)
+		getGame := Manager.GetGame
+		exists, err := Manager.GameExists(ctx, gameKey)
+		if err != nil {
+			return fmt.Errorf("error checking game existence: %w", err)
+		}
+		if !exists {
+			gameKey = fmt.Sprintf("archive:game:%s", gameID)
+			getGame = Manager.GetArchivedGame
+			exists, err = Manager.GameExists(ctx, gameKey)
+			if err != nil {
+				return fmt.Errorf("error checking game existence: %w", err)
+			}
+		}
+		if !exists {
+			return fmt.Errorf("game %s: %w", gameID, errGameMissing)
+		}
+
+		previous, err := getGame(ctx, gameID)
+		if err != nil {
+			return err
+		}
+
+		fields := map[string]interface{}{
+			"status": status,
+		}
+		if status == "live" || status == "final" {
+			fields["home_score"] = homeScore
+			fields["away_score"] = awayScore
+		}
+		if status == "final" {
+			winner := homeTeamAbbr
+			if awayScore > homeScore {
+				winner = awayTeamAbbr
+			}
+			fields["winner"] = winner
+
+			// grade the closing moneyline we captured for the home team
+			if previous["home_team_odds"] != "" {
+				fields["home_team_odds_result"] = "lost"
+				if winner == homeTeamAbbr {
+					fields["home_team_odds_result"] = "won"
+				}
+			}
+		}
+
+		_, err = updateGame(ctx, events.StatusChanged, gameID, gameKey, observed.of(games.GroupResults), previous, fields)
+		if err != nil {
+			return err
+		}
+		slog.DebugContext(ctx, "result stored", "game_id", gameID, "status", status)
+
 	default:
 		return fmt.Errorf("unknown queue: %s", queue)
 	}
@@ -258,6 +485,62 @@ func storeData(ctx context.Context, queue string, data map[string]interface{}) e
 	return nil
 }
 
+// updateGame writes fields to the game at gameKey, stamped with observed, and, if that
+// changed any of them, publishes an event of eventType and evaluates alert rules. previous
+// is the game as it was before the update; the game as it is after the update is returned.
+// Data observed before what's stored isn't written, that's games.ErrOutOfOrder.
+func updateGame(ctx context.Context, eventType, gameID, gameKey string, observed games.Observation, previous map[string]string, fields map[string]interface{}) (map[string]string, error) {
+	err := Manager.UpdateObservedGame(ctx, gameKey, observed, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]string)
+	current := make(map[string]string, len(previous)+len(fields))
+	for field, value := range previous {
+		current[field] = value
+	}
+	for field, value := range fields {
+		newValue := fmt.Sprint(value)
+		if newValue != previous[field] {
+			changes[field] = newValue
+		}
+		current[field] = newValue
+	}
+	if len(changes) == 0 {
+		return current, nil
+	}
+	slog.InfoContext(ctx, "game updated", "game_id", gameID, "type", eventType, "changes", changes)
+
+	if Alerts != nil {
+		Alerts.Evaluate(ctx, gameID, previous, current)
+	}
+
+	event := events.GameEvent{
+		Type:      eventType,
+		GameID:    gameID,
+		HomeTeam:  previous["home_team"],
+		AwayTeam:  previous["away_team"],
+		Changes:   changes,
+		Timestamp: time.Now().UTC(),
+		// lets subscribers trace a change back to the provider response behind it
+		CorrelationID: correlation.FromContext(ctx),
+	}
+	// webhooks are dispatched here rather than off the hub so that only the replica
+	// that consumed the message delivers them
+	if Webhooks != nil {
+		Webhooks.Dispatch(ctx, event)
+	}
+	if Events != nil {
+		// the game is already stored, a missed notification isn't worth redelivering the message for
+		err = Events.Publish(ctx, event)
+		if err != nil {
+			slog.ErrorContext(ctx, "error publishing game event", "type", eventType, "game_id", gameID, "err", err)
+		}
+	}
+	return current, nil
+}
+
 func extractTeams(eventName string) (homeTeam, awayTeam string, err error) {
 	// Normalize the event name: convert to lowercase and remove special characters
 	reg := regexp.MustCompile(`[^a-z0-9\s]+`) // Keep only alphanumeric and spaces
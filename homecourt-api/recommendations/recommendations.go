@@ -0,0 +1,201 @@
+package recommendations
+
+import (
+	"encoding/json"
+	"fmt"
+	"homecourt-api/games"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	FactorPrice           = "price"
+	FactorCompetitiveness = "competitiveness"
+	FactorStars           = "stars"
+)
+
+// Weights sets how much each factor counts towards a game's score. They don't have to
+// add up to 1, scores are normalized by the weights of the factors that were available.
+type Weights map[string]float64
+
+var DefaultWeights = Weights{
+	FactorPrice:           0.5,
+	FactorCompetitiveness: 0.3,
+	FactorStars:           0.2,
+}
+
+// ParseWeights reads weights like "price=0.6,stars=0.4". Factors that aren't mentioned
+// keep their default weight.
+func ParseWeights(s string) (Weights, error) {
+	weights := Weights{}
+	for factor, weight := range DefaultWeights {
+		weights[factor] = weight
+	}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		factor, value, ok := strings.Cut(pair, "=")
+		factor = strings.TrimSpace(factor)
+		if _, known := DefaultWeights[factor]; !ok || !known {
+			return nil, fmt.Errorf("invalid weight %q, expected one of price, competitiveness, stars", pair)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight for %s: %q", factor, value)
+		}
+		weights[factor] = weight
+	}
+	return weights, nil
+}
+
+// Factor is one part of a game's score, kept so fans can see why a game ranked where it did.
+type Factor struct {
+	Name      string  `json:"name"`
+	Score     float64 `json:"score"`
+	Weight    float64 `json:"weight"`
+	Available bool    `json:"available"`
+	Detail    string  `json:"detail"`
+}
+
+type Recommendation struct {
+	GameID  string            `json:"game_id"`
+	Score   float64           `json:"score"`
+	Factors []Factor          `json:"factors"`
+	Game    map[string]string `json:"game"`
+}
+
+// Median of prices, 0 when there are none.
+func Median(prices []float64) float64 {
+	if len(prices) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), prices...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// Score rates a game from 0 to 1. medianPrice is the team's median lowest ticket price,
+// 0 if it isn't known.
+func Score(gameID string, game map[string]string, medianPrice float64, weights Weights) Recommendation {
+	factors := []Factor{
+		priceFactor(game, medianPrice),
+		competitivenessFactor(game),
+		starsFactor(game),
+	}
+
+	var total, weightSum float64
+	for i := range factors {
+		factors[i].Weight = weights[factors[i].Name]
+		if factors[i].Available {
+			total += factors[i].Score * factors[i].Weight
+			weightSum += factors[i].Weight
+		}
+	}
+
+	score := 0.0
+	if weightSum > 0 {
+		score = total / weightSum
+	}
+	return Recommendation{GameID: gameID, Score: round(score), Factors: factors, Game: game}
+}
+
+// Rank sorts recommendations best first.
+func Rank(recommendations []Recommendation) {
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+}
+
+// priceFactor scores the lowest ticket price against the team's median: 0.5 at the median,
+// 1 at half of it or less, 0.25 at double.
+func priceFactor(game map[string]string, medianPrice float64) Factor {
+	factor := Factor{Name: FactorPrice}
+	price, err := games.ParsePrice(game["lowest_ticket_price"])
+	if err != nil || price <= 0 {
+		factor.Detail = "no ticket price yet"
+		return factor
+	}
+	if medianPrice <= 0 {
+		factor.Detail = "no price history for this team yet"
+		return factor
+	}
+
+	factor.Available = true
+	factor.Score = round(math.Min(1, medianPrice/(2*price)))
+	factor.Detail = fmt.Sprintf("$%.2f vs a median of $%.2f (%+.0f%%)", price, medianPrice, (price/medianPrice-1)*100)
+	return factor
+}
+
+// competitivenessFactor scores how close the moneyline is to a coin flip.
+func competitivenessFactor(game map[string]string) Factor {
+	factor := Factor{Name: FactorCompetitiveness}
+	probability, err := games.ImpliedProbability(game["home_team_odds"])
+	if err != nil {
+		factor.Detail = "no odds yet"
+		return factor
+	}
+
+	factor.Available = true
+	factor.Score = round(1 - math.Abs(probability-0.5)*2)
+	factor.Detail = fmt.Sprintf("%s gives %s a %.0f%% chance to win", game["home_team_odds"], game["home_team"], probability*100)
+	return factor
+}
+
+// how much each injury status takes off the stars score
+var injuryPenalties = map[string]float64{
+	"out":          0.25,
+	"doubtful":     0.15,
+	"questionable": 0.05,
+}
+
+// starsFactor scores who's expected to play. The injuries feed doesn't say who the stars are,
+// so every missing player counts the same. Without an injury report for the game there's no
+// telling, rather than a full roster.
+func starsFactor(game map[string]string) Factor {
+	injuries := game["injured_players"]
+	if injuries == "" {
+		return Factor{Name: FactorStars, Detail: "no injury report yet"}
+	}
+	factor := Factor{Name: FactorStars, Available: true, Score: 1, Detail: "no reported injuries"}
+
+	var injured []struct {
+		Team       string `json:"team"`
+		PlayerName string `json:"player_name"`
+		Status     string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(injuries), &injured); err != nil {
+		factor.Available = false
+		factor.Score = 0
+		factor.Detail = "injury report unreadable"
+		return factor
+	}
+	if len(injured) == 0 {
+		return factor
+	}
+
+	var missing []string
+	for _, player := range injured {
+		penalty, ok := injuryPenalties[strings.ToLower(player.Status)]
+		if !ok {
+			continue
+		}
+		factor.Score -= penalty
+		missing = append(missing, fmt.Sprintf("%s (%s, %s)", player.PlayerName, player.Team, strings.ToLower(player.Status)))
+	}
+	factor.Score = round(math.Max(0, factor.Score))
+	if len(missing) > 0 {
+		factor.Detail = strings.Join(missing, ", ")
+	}
+	return factor
+}
+
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
@@ -0,0 +1,101 @@
+package recommendations
+
+import "testing"
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []float64
+		want   float64
+	}{
+		{"none", nil, 0},
+		{"one", []float64{80}, 80},
+		{"odd length", []float64{120, 40, 95}, 95},
+		{"even length", []float64{120, 40, 95, 60}, 77.5},
+		{"duplicates", []float64{50, 50, 50, 200}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Median(tt.prices); got != tt.want {
+				t.Errorf("Median(%v) = %v, want %v", tt.prices, got, tt.want)
+			}
+		})
+	}
+
+	prices := []float64{3, 1, 2}
+	Median(prices)
+	if prices[0] != 3 || prices[1] != 1 || prices[2] != 2 {
+		t.Errorf("Median reordered its input to %v", prices)
+	}
+}
+
+func TestScore(t *testing.T) {
+	game := func(price, odds, injuries string) map[string]string {
+		game := map[string]string{"home_team": "NYK"}
+		if price != "" {
+			game["lowest_ticket_price"] = price
+		}
+		if odds != "" {
+			game["home_team_odds"] = odds
+		}
+		if injuries != "" {
+			game["injured_players"] = injuries
+		}
+		return game
+	}
+	const tatumOut = `[{"team":"BOS","player_name":"Jayson Tatum","status":"Out"}]`
+
+	tests := []struct {
+		name        string
+		game        map[string]string
+		medianPrice float64
+		weights     Weights
+		want        float64
+		// factor scores in the order of Score, -1 for unavailable
+		factors [3]float64
+	}{
+		{"every factor", game("$100.00", "-150", tatumOut), 100, DefaultWeights, 0.64, [3]float64{0.5, 0.8, 0.75}},
+		{"half the median", game("$50.00", "+100", "[]"), 100, DefaultWeights, 1, [3]float64{1, 1, 1}},
+		{"double the median", game("$200.00", "+110", "[]"), 100, DefaultWeights, 0.611, [3]float64{0.25, 0.952, 1}},
+		{"underdog home team", game("$100.00", "+300", "[]"), 100, DefaultWeights, 0.6, [3]float64{0.5, 0.5, 1}},
+		{"no odds", game("$100.00", "", tatumOut), 100, DefaultWeights, 0.571, [3]float64{0.5, -1, 0.75}},
+		{"unreadable odds", game("$100.00", "even", tatumOut), 100, DefaultWeights, 0.571, [3]float64{0.5, -1, 0.75}},
+		{"no price", game("", "-150", tatumOut), 100, DefaultWeights, 0.78, [3]float64{-1, 0.8, 0.75}},
+		{"no price history", game("$100.00", "-150", tatumOut), 0, DefaultWeights, 0.78, [3]float64{-1, 0.8, 0.75}},
+		{"no injury report", game("$100.00", "-150", ""), 100, DefaultWeights, 0.612, [3]float64{0.5, 0.8, -1}},
+		{"unreadable injury report", game("$100.00", "-150", "<html>"), 100, DefaultWeights, 0.612, [3]float64{0.5, 0.8, -1}},
+		{
+			"injury statuses",
+			game("$100.00", "-150", `[{"status":"Out"},{"status":"Doubtful"},{"status":"Questionable"},{"status":"Probable"}]`),
+			100, DefaultWeights, 0.6, [3]float64{0.5, 0.8, 0.55},
+		},
+		{
+			"injuries floor at 0",
+			game("$100.00", "-150", `[{"status":"Out"},{"status":"Out"},{"status":"Out"},{"status":"Out"},{"status":"Out"}]`),
+			100, DefaultWeights, 0.49, [3]float64{0.5, 0.8, 0},
+		},
+		{"nothing known", game("", "", ""), 0, DefaultWeights, 0, [3]float64{-1, -1, -1}},
+		{"price only", game("$50.00", "-150", tatumOut), 100, Weights{FactorPrice: 1}, 1, [3]float64{1, 0.8, 0.75}},
+		{"stars only", game("$50.00", "-150", tatumOut), 100, Weights{FactorStars: 1}, 0.75, [3]float64{1, 0.8, 0.75}},
+		{"zero weights", game("$50.00", "-150", tatumOut), 100, Weights{}, 0, [3]float64{1, 0.8, 0.75}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score("NYK BOS 01.10.2030", tt.game, tt.medianPrice, tt.weights)
+			if got.Score != tt.want {
+				t.Errorf("score = %v, want %v", got.Score, tt.want)
+			}
+			if len(got.Factors) != 3 {
+				t.Fatalf("got %d factors, want 3", len(got.Factors))
+			}
+			for i, factor := range got.Factors {
+				if want := tt.factors[i]; factor.Available != (want >= 0) || (want >= 0 && factor.Score != want) {
+					t.Errorf("%s = %v (available %t), want %v", factor.Name, factor.Score, factor.Available, want)
+				}
+				if factor.Weight != tt.weights[factor.Name] {
+					t.Errorf("%s weight = %v, want %v", factor.Name, factor.Weight, tt.weights[factor.Name])
+				}
+			}
+		})
+	}
+}
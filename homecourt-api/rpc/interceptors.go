@@ -0,0 +1,184 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"homecourt-api/middleware"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var tracer = otel.Tracer("homecourt-api/rpc")
+
+var rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "homecourt_grpc_request_duration_seconds",
+	Help:    "gRPC calls, by method and status code. Streams are timed until they end.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "code"})
+
+// interceptor does for every call what the HTTP middleware does for every request: starts a
+// span continuing the caller's trace, times it, and checks its API key and rate limits.
+// Keys are sent as x-api-key metadata.
+type interceptor struct {
+	keys *middleware.APIKeys
+}
+
+func (i *interceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, finish, err := i.begin(ctx, info.FullMethod, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) })
+	if err != nil {
+		finish(err)
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	finish(err)
+	return resp, err
+}
+
+func (i *interceptor) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, finish, err := i.begin(ss.Context(), info.FullMethod, ss.SetHeader)
+	if err != nil {
+		finish(err)
+		return err
+	}
+	err = handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	finish(err)
+	return err
+}
+
+// begin starts a call to method and checks whether it may go ahead. finish has to be called
+// with the call's error either way.
+func (i *interceptor) begin(ctx context.Context, method string, setHeader func(metadata.MD) error) (context.Context, func(error), error) {
+	start := time.Now()
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	ctx, span := tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+		),
+	)
+
+	var decision middleware.Decision
+	finish := func(err error) {
+		code := status.Code(err)
+		rpcDuration.WithLabelValues(method, code.String()).Observe(time.Since(start).Seconds())
+		if decision.Key.ID != "" {
+			i.keys.RecordUsage(ctx, decision.Key, method, httpStatus(code))
+		}
+
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+		if httpStatus(code) >= http.StatusInternalServerError {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	if i.keys == nil {
+		return ctx, finish, nil
+	}
+
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	decision, err := i.keys.Check(ctx, first(md.Get("x-api-key")), i.keys.ClientIP(remoteAddr, md.Get("x-forwarded-for")))
+	switch {
+	case errors.Is(err, middleware.ErrInvalidKey), errors.Is(err, middleware.ErrMissingKey):
+		return ctx, finish, status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		return ctx, finish, status.Error(codes.Internal, "failed to verify api key")
+	}
+
+	if decision.Limited && !decision.Result.Allowed {
+		retryAfter := strconv.Itoa(int(math.Ceil(decision.Result.RetryAfter.Seconds())))
+		// like the HTTP API's Retry-After, the error is what clients see though
+		setHeader(metadata.Pairs("retry-after", retryAfter))
+		return ctx, finish, status.Error(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded, retry in %ss", retryAfter))
+	}
+	return ctx, finish, nil
+}
+
+// contextStream hands the call's context, with its span, to stream handlers.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataCarrier lets the propagator read a traceparent from incoming metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	return first(metadata.MD(c).Get(key))
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// httpStatus maps a status code onto the HTTP one it corresponds to, for usage stats that
+// are kept by status class.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
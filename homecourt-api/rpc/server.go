@@ -0,0 +1,254 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"homecourt-api/events"
+	"homecourt-api/games"
+	"homecourt-api/homecourtpb"
+	"homecourt-api/middleware"
+	"homecourt-api/teams"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var Manager games.GamesManager
+var Events *events.Hub
+
+const (
+	// how long a call may spend reading games from Redis
+	lookupTimeout = 2 * time.Second
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type server struct {
+	homecourtpb.UnimplementedHomecourtServer
+}
+
+// NewServer returns a gRPC server with the Homecourt service registered. Calls are traced,
+// timed and, when keys is set, checked against API keys and rate limited like HTTP requests.
+func NewServer(keys *middleware.APIKeys) *grpc.Server {
+	i := &interceptor{keys: keys}
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	)
+	homecourtpb.RegisterHomecourtServer(s, &server{})
+	return s
+}
+
+func (s *server) ListTeams(ctx context.Context, req *homecourtpb.ListTeamsRequest) (*homecourtpb.ListTeamsResponse, error) {
+	abbreviations := make([]string, 0, len(teams.Registry))
+	for abbreviation := range teams.Registry {
+		abbreviations = append(abbreviations, abbreviation)
+	}
+	sort.Strings(abbreviations)
+
+	resp := &homecourtpb.ListTeamsResponse{}
+	for _, abbreviation := range abbreviations {
+		team := teams.Registry[abbreviation]
+		resp.Teams = append(resp.Teams, &homecourtpb.Team{
+			Abbreviation: team.Abbreviation,
+			Name:         team.Name,
+			City:         team.City,
+			Timezone:     team.Timezone,
+		})
+	}
+	return resp, nil
+}
+
+var sorts = map[homecourtpb.ListGamesRequest_Sort]string{
+	homecourtpb.ListGamesRequest_SORT_TIP_OFF: games.SortTipOff,
+	homecourtpb.ListGamesRequest_SORT_PRICE:   games.SortPrice,
+	homecourtpb.ListGamesRequest_SORT_ODDS:    games.SortOdds,
+}
+
+var homeLines = map[homecourtpb.ListGamesRequest_HomeLine]string{
+	homecourtpb.ListGamesRequest_HOME_LINE_ANY:      "",
+	homecourtpb.ListGamesRequest_HOME_LINE_FAVORITE: games.LineFavorite,
+	homecourtpb.ListGamesRequest_HOME_LINE_UNDERDOG: games.LineUnderdog,
+}
+
+func (s *server) ListGames(ctx context.Context, req *homecourtpb.ListGamesRequest) (*homecourtpb.ListGamesResponse, error) {
+	query := games.GameQuery{
+		From:        time.Now(),
+		Team:        strings.ToUpper(req.Team),
+		Venue:       req.Venue,
+		MaxPrice:    req.MaxPrice,
+		HomeLine:    homeLines[req.HomeLine],
+		HasInjuries: req.HasInjuries,
+		Sort:        sorts[req.Sort],
+		Desc:        req.Descending,
+		Limit:       int(req.PageSize),
+		Cursor:      req.PageToken,
+	}
+	if req.From != nil {
+		query.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		query.To = req.To.AsTime()
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultPageSize
+	}
+	if query.Limit < 0 || query.Limit > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxPageSize)
+	}
+	if query.Team != "" {
+		if _, ok := teams.Registry[query.Team]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown team: %s", query.Team)
+		}
+	}
+	if err := query.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	page, nextCursor, failed, err := games.Search(ctx, Manager, query)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &homecourtpb.ListGamesResponse{}
+	for _, result := range failed {
+		slog.ErrorContext(ctx, "error fetching game", "game_id", result.GameID, "err", result.Err)
+		resp.Errors = append(resp.Errors, &homecourtpb.GameError{GameId: result.GameID, Error: "failed to fetch game data"})
+	}
+	for _, game := range page {
+		resp.Games = append(resp.Games, toGame(game["game_id"], game, false))
+	}
+	resp.NextPageToken = nextCursor
+	return resp, nil
+}
+
+func (s *server) GetGame(ctx context.Context, req *homecourtpb.GetGameRequest) (*homecourtpb.Game, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	data, err := Manager.GetGame(ctx, req.Id)
+	archived := false
+	if errors.Is(err, games.ErrGameNotFound) {
+		// finished games are only in the archive
+		data, err = Manager.GetArchivedGame(ctx, req.Id)
+		archived = true
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toGame(req.Id, data, archived), nil
+}
+
+// grpcError maps GamesManager errors onto gRPC status codes.
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, games.ErrGameNotFound):
+		return status.Error(codes.NotFound, "game not found")
+	case errors.Is(err, games.ErrInvalidTeam):
+		return status.Error(codes.InvalidArgument, "unknown team")
+	case errors.Is(err, games.ErrUnavailable), errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.Unavailable, "failed to fetch game data")
+	default:
+		slog.Error("rpc error", "err", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func (s *server) WatchGames(req *homecourtpb.WatchGamesRequest, stream homecourtpb.Homecourt_WatchGamesServer) error {
+	var subscribedTeams []string
+	for _, team := range req.Teams {
+		team = strings.ToUpper(strings.TrimSpace(team))
+		if _, ok := teams.Registry[team]; !ok {
+			return status.Errorf(codes.InvalidArgument, "unknown team: %s", team)
+		}
+		subscribedTeams = append(subscribedTeams, team)
+	}
+
+	gameEvents, unsubscribe := Events.Subscribe(subscribedTeams)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-gameEvents:
+			// the hub is shutting down
+			if !ok {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+			err := stream.Send(&homecourtpb.GameEvent{
+				Type:      event.Type,
+				GameId:    event.GameID,
+				HomeTeam:  event.HomeTeam,
+				AwayTeam:  event.AwayTeam,
+				Changes:   event.Changes,
+				Timestamp: timestamppb.New(event.Timestamp),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toGame converts a game hash into its protobuf form. Fields the game doesn't have yet
+// are left unset.
+func toGame(gameID string, data map[string]string, archived bool) *homecourtpb.Game {
+	game := &homecourtpb.Game{
+		Id:           gameID,
+		HomeTeam:     data["home_team"],
+		AwayTeam:     data["away_team"],
+		Venue:        data["venueName"],
+		Hashtag:      data["hashtag"],
+		Status:       data["status"],
+		Winner:       data["winner"],
+		HomeTeamOdds: data["home_team_odds"],
+		Archived:     archived,
+	}
+	if startTime, err := time.Parse(time.RFC3339, data["start_time"]); err == nil {
+		game.StartTime = timestamppb.New(startTime)
+	}
+	game.Week = atoi32(data["week"])
+	game.HomeScore = atoi32(data["home_score"])
+	game.AwayScore = atoi32(data["away_score"])
+	if price, err := games.ParsePrice(data["lowest_ticket_price"]); err == nil {
+		game.LowestTicketPrice = &price
+	}
+	if probability, err := games.ImpliedProbability(data["home_team_odds"]); err == nil {
+		game.HomeWinProbability = &probability
+	}
+	if injuries := data["injured_players"]; injuries != "" {
+		var injured []struct {
+			Team       string `json:"team"`
+			PlayerName string `json:"player_name"`
+			Status     string `json:"status"`
+		}
+		if err := json.Unmarshal([]byte(injuries), &injured); err != nil {
+			slog.Warn("error decoding injuries", "game_id", gameID, "err", err)
+		}
+		for _, player := range injured {
+			game.Injuries = append(game.Injuries, &homecourtpb.Injury{Team: player.Team, PlayerName: player.PlayerName, Status: player.Status})
+		}
+	}
+	return game
+}
+
+func atoi32(s string) int32 {
+	n, _ := strconv.Atoi(s)
+	return int32(n)
+}
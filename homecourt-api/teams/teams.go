@@ -0,0 +1,61 @@
+package teams
+
+import (
+	"fmt"
+	"time"
+)
+
+// Team is a single NBA franchise and the arena it plays home games in.
+type Team struct {
+	Abbreviation string `json:"abbreviation"`
+	Name         string `json:"name"`
+	City         string `json:"city"`
+	Timezone     string `json:"timezone"` // IANA name of the home arena's timezone
+}
+
+// Registry maps team abbreviations (as stored on game hashes) to their team.
+var Registry = map[string]Team{
+	"ATL": {Abbreviation: "ATL", Name: "Atlanta Hawks", City: "Atlanta", Timezone: "America/New_York"},
+	"BOS": {Abbreviation: "BOS", Name: "Boston Celtics", City: "Boston", Timezone: "America/New_York"},
+	"BKN": {Abbreviation: "BKN", Name: "Brooklyn Nets", City: "Brooklyn", Timezone: "America/New_York"},
+	"CHA": {Abbreviation: "CHA", Name: "Charlotte Hornets", City: "Charlotte", Timezone: "America/New_York"},
+	"CHI": {Abbreviation: "CHI", Name: "Chicago Bulls", City: "Chicago", Timezone: "America/Chicago"},
+	"CLE": {Abbreviation: "CLE", Name: "Cleveland Cavaliers", City: "Cleveland", Timezone: "America/New_York"},
+	"DAL": {Abbreviation: "DAL", Name: "Dallas Mavericks", City: "Dallas", Timezone: "America/Chicago"},
+	"DEN": {Abbreviation: "DEN", Name: "Denver Nuggets", City: "Denver", Timezone: "America/Denver"},
+	"DET": {Abbreviation: "DET", Name: "Detroit Pistons", City: "Detroit", Timezone: "America/Detroit"},
+	"GSW": {Abbreviation: "GSW", Name: "Golden State Warriors", City: "San Francisco", Timezone: "America/Los_Angeles"},
+	"HOU": {Abbreviation: "HOU", Name: "Houston Rockets", City: "Houston", Timezone: "America/Chicago"},
+	"IND": {Abbreviation: "IND", Name: "Indiana Pacers", City: "Indianapolis", Timezone: "America/Indiana/Indianapolis"},
+	"LAC": {Abbreviation: "LAC", Name: "LA Clippers", City: "Los Angeles", Timezone: "America/Los_Angeles"},
+	"LAL": {Abbreviation: "LAL", Name: "Los Angeles Lakers", City: "Los Angeles", Timezone: "America/Los_Angeles"},
+	"MEM": {Abbreviation: "MEM", Name: "Memphis Grizzlies", City: "Memphis", Timezone: "America/Chicago"},
+	"MIA": {Abbreviation: "MIA", Name: "Miami Heat", City: "Miami", Timezone: "America/New_York"},
+	"MIL": {Abbreviation: "MIL", Name: "Milwaukee Bucks", City: "Milwaukee", Timezone: "America/Chicago"},
+	"MIN": {Abbreviation: "MIN", Name: "Minnesota Timberwolves", City: "Minneapolis", Timezone: "America/Chicago"},
+	"NOP": {Abbreviation: "NOP", Name: "New Orleans Pelicans", City: "New Orleans", Timezone: "America/Chicago"},
+	"NYK": {Abbreviation: "NYK", Name: "New York Knicks", City: "New York", Timezone: "America/New_York"},
+	"OKC": {Abbreviation: "OKC", Name: "Oklahoma City Thunder", City: "Oklahoma City", Timezone: "America/Chicago"},
+	"ORL": {Abbreviation: "ORL", Name: "Orlando Magic", City: "Orlando", Timezone: "America/New_York"},
+	"PHI": {Abbreviation: "PHI", Name: "Philadelphia 76ers", City: "Philadelphia", Timezone: "America/New_York"},
+	"PHX": {Abbreviation: "PHX", Name: "Phoenix Suns", City: "Phoenix", Timezone: "America/Phoenix"},
+	"POR": {Abbreviation: "POR", Name: "Portland Trail Blazers", City: "Portland", Timezone: "America/Los_Angeles"},
+	"SAC": {Abbreviation: "SAC", Name: "Sacramento Kings", City: "Sacramento", Timezone: "America/Los_Angeles"},
+	"SAS": {Abbreviation: "SAS", Name: "San Antonio Spurs", City: "San Antonio", Timezone: "America/Chicago"},
+	"TOR": {Abbreviation: "TOR", Name: "Toronto Raptors", City: "Toronto", Timezone: "America/Toronto"},
+	"UTA": {Abbreviation: "UTA", Name: "Utah Jazz", City: "Salt Lake City", Timezone: "America/Denver"},
+	"WAS": {Abbreviation: "WAS", Name: "Washington Wizards", City: "Washington", Timezone: "America/New_York"},
+}
+
+// Location returns the timezone of a team's home arena.
+func Location(abbreviation string) (*time.Location, error) {
+	team, ok := Registry[abbreviation]
+	if !ok {
+		return nil, fmt.Errorf("unknown team: %s", abbreviation)
+	}
+	loc, err := time.LoadLocation(team.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timezone %s for %s: %v", team.Timezone, abbreviation, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,26 @@
+package tracing
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// HeaderCarrier reads and writes trace context in the headers of an AMQP message, for
+// otel.GetTextMapPropagator().
+type HeaderCarrier amqp.Table
+
+func (c HeaderCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
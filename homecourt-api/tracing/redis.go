@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("homecourt-api/tracing")
+
+// RedisHook adds a span for every command a client sends, under the span of the context the
+// command was sent with. Add it to each client with AddHook.
+type RedisHook struct{}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		// arguments are left out, they carry sessions and API key hashes
+		ctx, span := startRedis(ctx, cmd.Name(), attribute.String("db.operation.name", cmd.Name()))
+		err := next(ctx, cmd)
+		endRedis(span, err)
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = cmd.Name()
+		}
+		ctx, span := startRedis(ctx, "pipeline",
+			attribute.String("db.operation.name", "pipeline"),
+			attribute.StringSlice("db.redis.commands", names),
+		)
+		err := next(ctx, cmds)
+		endRedis(span, err)
+		return err
+	}
+}
+
+func startRedis(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "redis "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("db.system", "redis"))...),
+	)
+}
+
+// missing keys aren't failures, nor are scripts that weren't loaded yet since Script.Run
+// loads them and retries
+func endRedis(span trace.Span, err error) {
+	if errors.Is(err, redis.Nil) || redis.HasErrorPrefix(err, "NOSCRIPT") {
+		err = nil
+	}
+	End(span, err)
+}
@@ -0,0 +1,259 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"homecourt-api/metrics"
+	"homecourt-api/tracing"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// how long a login lasts
+const sessionTTL = 30 * 24 * time.Hour
+
+var (
+	ErrEmailTaken         = errors.New("email is already registered")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidSession     = errors.New("invalid or expired session")
+	ErrUserNotFound       = errors.New("user not found")
+)
+
+// User is a Homecourt account.
+type User struct {
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	PasswordHash  []byte    `json:"password_hash,omitempty"`
+	FavoriteTeams []string  `json:"favorite_teams"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Public is the user as shown to clients, without the password hash.
+func (u User) Public() User {
+	u.PasswordHash = nil
+	if u.FavoriteTeams == nil {
+		u.FavoriteTeams = []string{}
+	}
+	return u
+}
+
+// UsersManager defines the methods for managing accounts and their sessions.
+type UsersManager interface {
+	CreateUser(ctx context.Context, email, password string) (User, error)
+	Authenticate(ctx context.Context, email, password string) (User, error)
+	GetUser(ctx context.Context, userID string) (User, error)
+	SetFavoriteTeams(ctx context.Context, userID string, teams []string) (User, error)
+
+	// Sessions are opaque bearer tokens. Only their hash is stored.
+	CreateSession(ctx context.Context, userID string) (string, error)
+	GetSessionUser(ctx context.Context, token string) (User, error)
+	DeleteSession(ctx context.Context, token string) error
+}
+
+type redisUsersManager struct {
+	client *redis.Client
+}
+
+func NewUsersManager(addr string) (UsersManager, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return &redisUsersManager{client: client}, nil
+}
+
+// ValidateSignup checks an email and password before an account is created.
+func ValidateSignup(email, password string) error {
+	if _, err := mail.ParseAddress(email); err != nil {
+		return fmt.Errorf("invalid email address")
+	}
+	if len(password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+	// bcrypt ignores everything past 72 bytes
+	if len(password) > 72 {
+		return fmt.Errorf("password must be at most 72 bytes")
+	}
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func userKey(userID string) string {
+	return fmt.Sprintf("user:%s", userID)
+}
+
+func emailKey(email string) string {
+	return fmt.Sprintf("user:email:%s", normalizeEmail(email))
+}
+
+func sessionKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("session:%s", hex.EncodeToString(sum[:]))
+}
+
+func (r *redisUsersManager) CreateUser(ctx context.Context, email, password string) (User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to generate user id: %v", err)
+	}
+
+	user := User{
+		ID:            id,
+		Email:         normalizeEmail(email),
+		PasswordHash:  hash,
+		FavoriteTeams: []string{},
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	// claiming the email first keeps two signups for the same address from both succeeding
+	claimed, err := r.client.SetNX(ctx, emailKey(email), user.ID, 0).Result()
+	if err != nil {
+		return User{}, fmt.Errorf("failed to claim email: %v", err)
+	}
+	if !claimed {
+		return User{}, ErrEmailTaken
+	}
+
+	err = r.saveUser(ctx, user)
+	if err != nil {
+		r.client.Del(ctx, emailKey(email))
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (r *redisUsersManager) saveUser(ctx context.Context, user User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user: %v", err)
+	}
+	err = r.client.Set(ctx, userKey(user.ID), data, 0).Err()
+	if err != nil {
+		return fmt.Errorf("failed to store user %s: %v", user.ID, err)
+	}
+	return nil
+}
+
+func (r *redisUsersManager) Authenticate(ctx context.Context, email, password string) (User, error) {
+	userID, err := r.client.Get(ctx, emailKey(email)).Result()
+	if err == redis.Nil {
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("failed to look up email: %v", err)
+	}
+
+	user, err := r.GetUser(ctx, userID)
+	if err == ErrUserNotFound {
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	err = bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password))
+	if err != nil {
+		return User{}, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+func (r *redisUsersManager) GetUser(ctx context.Context, userID string) (User, error) {
+	data, err := r.client.Get(ctx, userKey(userID)).Bytes()
+	if err == redis.Nil {
+		return User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("failed to get user %s: %v", userID, err)
+	}
+
+	var user User
+	err = json.Unmarshal(data, &user)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to unmarshal user %s: %v", userID, err)
+	}
+	return user, nil
+}
+
+func (r *redisUsersManager) SetFavoriteTeams(ctx context.Context, userID string, teams []string) (User, error) {
+	user, err := r.GetUser(ctx, userID)
+	if err != nil {
+		return User{}, err
+	}
+
+	user.FavoriteTeams = teams
+	err = r.saveUser(ctx, user)
+	if err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (r *redisUsersManager) CreateSession(ctx context.Context, userID string) (string, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate session token: %v", err)
+	}
+
+	err = r.client.Set(ctx, sessionKey(token), userID, sessionTTL).Err()
+	if err != nil {
+		return "", fmt.Errorf("failed to store session: %v", err)
+	}
+	return token, nil
+}
+
+func (r *redisUsersManager) GetSessionUser(ctx context.Context, token string) (User, error) {
+	userID, err := r.client.Get(ctx, sessionKey(token)).Result()
+	if err == redis.Nil {
+		return User{}, ErrInvalidSession
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("failed to get session: %v", err)
+	}
+
+	user, err := r.GetUser(ctx, userID)
+	if err == ErrUserNotFound {
+		return User{}, ErrInvalidSession
+	}
+	return user, err
+}
+
+func (r *redisUsersManager) DeleteSession(ctx context.Context, token string) error {
+	err := r.client.Del(ctx, sessionKey(token)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %v", err)
+	}
+	return nil
+}
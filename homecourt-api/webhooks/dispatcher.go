@@ -0,0 +1,146 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"homecourt-api/events"
+	"homecourt-api/outbound"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	maxAttempts    = 6
+	initialBackoff = 2 * time.Second // doubled after every failed attempt, so the last retry is ~1 minute out
+)
+
+// Dispatcher delivers game events to the webhooks subscribed to them.
+type Dispatcher struct {
+	Webhooks WebhooksManager
+	Client   *http.Client
+}
+
+func NewDispatcher(webhooks WebhooksManager) *Dispatcher {
+	return &Dispatcher{
+		Webhooks: webhooks,
+		Client:   outbound.NewClient(10 * time.Second),
+	}
+}
+
+// Sign returns the X-Homecourt-Signature for a delivery: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook's secret. Receivers should recompute it and
+// reject deliveries whose timestamp is too old.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatch delivers event to every webhook subscribed to its type. Deliveries and their
+// retries run in the background, so this only blocks on looking the webhooks up.
+func (d *Dispatcher) Dispatch(ctx context.Context, event events.GameEvent) {
+	webhooks, err := d.Webhooks.ListWebhooks(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "error listing webhooks", "type", event.Type, "err", err)
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.ErrorContext(ctx, "error marshalling event", "type", event.Type, "err", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(event.Type) {
+			continue
+		}
+		deliveryID, err := randomHex(8)
+		if err != nil {
+			slog.ErrorContext(ctx, "error generating delivery id", "err", err)
+			continue
+		}
+		// deliveries outlive the message but not the receiver, whose ctx is cancelled on shutdown
+		go d.deliver(ctx, webhook, deliveryID, event, body)
+	}
+}
+
+// deliver retries with exponential backoff until the webhook answers 2xx, maxAttempts is
+// reached or ctx is cancelled.
+func (d *Dispatcher) deliver(ctx context.Context, webhook Webhook, deliveryID string, event events.GameEvent, body []byte) {
+	backoff := initialBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		delivery := d.attempt(ctx, webhook, deliveryID, event, body)
+		delivery.Attempt = attempt
+
+		// an attempt cut short by shutdown is still worth logging
+		logCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		if err := d.Webhooks.LogDelivery(logCtx, delivery); err != nil {
+			slog.ErrorContext(ctx, "error logging delivery", "delivery_id", deliveryID, "err", err)
+		}
+		cancel()
+
+		if delivery.Success {
+			return
+		}
+		if attempt < maxAttempts {
+			select {
+			case <-ctx.Done():
+				slog.WarnContext(ctx, "abandoning webhook delivery", "delivery_id", deliveryID, "type", event.Type, "webhook_id", webhook.ID, "attempts", attempt)
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+	slog.WarnContext(ctx, "giving up on webhook delivery", "delivery_id", deliveryID, "type", event.Type, "webhook_id", webhook.ID, "attempts", maxAttempts)
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, webhook Webhook, deliveryID string, event events.GameEvent, body []byte) Delivery {
+	delivery := Delivery{
+		ID:          deliveryID,
+		WebhookID:   webhook.ID,
+		Event:       event.Type,
+		GameID:      event.GameID,
+		AttemptedAt: time.Now().UTC(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = fmt.Sprintf("failed to create request: %v", err)
+		return delivery
+	}
+
+	timestamp := strconv.FormatInt(delivery.AttemptedAt.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Homecourt/1.0")
+	req.Header.Set("X-Homecourt-Event", event.Type)
+	req.Header.Set("X-Homecourt-Delivery", deliveryID)
+	req.Header.Set("X-Homecourt-Timestamp", timestamp)
+	req.Header.Set("X-Homecourt-Signature", Sign(webhook.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := d.Client.Do(req)
+	delivery.Duration = time.Since(start).String()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Success {
+		delivery.Error = fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+	}
+	return delivery
+}
@@ -0,0 +1,244 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"homecourt-api/events"
+	"homecourt-api/metrics"
+	"homecourt-api/outbound"
+	"homecourt-api/tracing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// deliveries kept per webhook
+const deliveryLogSize = 100
+
+// ErrWebhookNotFound is returned when a webhook ID doesn't exist.
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// EventTypes are the game events a webhook can subscribe to. events.InjuryUpdated is
+// reserved: the receiver doesn't handle the injury feed yet, so nothing emits it.
+var EventTypes = map[string]bool{
+	events.PriceChanged:  true,
+	events.OddsChanged:   true,
+	events.Rescheduled:   true,
+	events.StatusChanged: true,
+}
+
+// Webhook is a partner endpoint that receives the game events it subscribed to.
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // signs deliveries, only shown when the webhook is created
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks a registration before it's stored. URLs that resolve to loopback, private
+// or link-local addresses are refused, the dispatcher's client refuses them again on delivery.
+func (w Webhook) Validate(ctx context.Context) error {
+	if err := outbound.CheckURL(ctx, w.URL); err != nil {
+		return err
+	}
+	if len(w.Events) == 0 {
+		return fmt.Errorf("at least one event type is required")
+	}
+	for _, eventType := range w.Events {
+		if eventType == events.InjuryUpdated {
+			return fmt.Errorf("event type %s is reserved, it isn't emitted yet", eventType)
+		}
+		if !EventTypes[eventType] {
+			return fmt.Errorf("unknown event type: %s", eventType)
+		}
+	}
+	return nil
+}
+
+// Subscribes reports whether the webhook wants events of eventType.
+func (w Webhook) Subscribes(eventType string) bool {
+	for _, t := range w.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one attempt at delivering an event to a webhook.
+type Delivery struct {
+	ID          string    `json:"id"` // shared by every attempt at the same event
+	WebhookID   string    `json:"webhook_id"`
+	Event       string    `json:"event"`
+	GameID      string    `json:"game_id"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Success     bool      `json:"success"`
+	Duration    string    `json:"duration"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// WebhooksManager defines the methods for storing webhooks and their delivery log.
+type WebhooksManager interface {
+	CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error)
+	GetWebhook(ctx context.Context, webhookID string) (Webhook, error)
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID string) error
+	LogDelivery(ctx context.Context, delivery Delivery) error
+	GetDeliveries(ctx context.Context, webhookID string, count int64) ([]Delivery, error)
+}
+
+type redisWebhooksManager struct {
+	client *redis.Client
+}
+
+func NewWebhooksManager(addr string) (WebhooksManager, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return &redisWebhooksManager{client: client}, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (r *redisWebhooksManager) CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to generate webhook id: %v", err)
+	}
+	webhook.ID = id
+	webhook.CreatedAt = time.Now().UTC()
+	if webhook.Secret == "" {
+		webhook.Secret, err = randomHex(32)
+		if err != nil {
+			return Webhook{}, fmt.Errorf("failed to generate webhook secret: %v", err)
+		}
+	}
+
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to marshal webhook: %v", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, fmt.Sprintf("webhook:%s", webhook.ID), data, 0)
+		pipe.SAdd(ctx, "webhooks", webhook.ID)
+		return nil
+	})
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to store webhook: %v", err)
+	}
+	return webhook, nil
+}
+
+func (r *redisWebhooksManager) GetWebhook(ctx context.Context, webhookID string) (Webhook, error) {
+	data, err := r.client.Get(ctx, fmt.Sprintf("webhook:%s", webhookID)).Bytes()
+	if err == redis.Nil {
+		return Webhook{}, ErrWebhookNotFound
+	}
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to get webhook %s: %v", webhookID, err)
+	}
+
+	var webhook Webhook
+	err = json.Unmarshal(data, &webhook)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to unmarshal webhook %s: %v", webhookID, err)
+	}
+	return webhook, nil
+}
+
+func (r *redisWebhooksManager) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	webhookIDs, err := r.client.SMembers(ctx, "webhooks").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %v", err)
+	}
+
+	webhooks := []Webhook{}
+	for _, webhookID := range webhookIDs {
+		webhook, err := r.GetWebhook(ctx, webhookID)
+		// deleted between reading the set and the webhook
+		if err == ErrWebhookNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+func (r *redisWebhooksManager) DeleteWebhook(ctx context.Context, webhookID string) error {
+	deleted, err := r.client.Del(ctx, fmt.Sprintf("webhook:%s", webhookID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %v", webhookID, err)
+	}
+	if deleted == 0 {
+		return ErrWebhookNotFound
+	}
+
+	err = r.client.SRem(ctx, "webhooks", webhookID).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %v", webhookID, err)
+	}
+	// the delivery log goes with it
+	return r.client.Del(ctx, fmt.Sprintf("webhook:%s:deliveries", webhookID)).Err()
+}
+
+func (r *redisWebhooksManager) LogDelivery(ctx context.Context, delivery Delivery) error {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery: %v", err)
+	}
+
+	key := fmt.Sprintf("webhook:%s:deliveries", delivery.WebhookID)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, data)
+		pipe.LTrim(ctx, key, 0, deliveryLogSize-1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to log delivery %s: %v", delivery.ID, err)
+	}
+	return nil
+}
+
+// GetDeliveries returns a webhook's most recent delivery attempts, newest first.
+func (r *redisWebhooksManager) GetDeliveries(ctx context.Context, webhookID string, count int64) ([]Delivery, error) {
+	entries, err := r.client.LRange(ctx, fmt.Sprintf("webhook:%s:deliveries", webhookID), 0, count-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries for webhook %s: %v", webhookID, err)
+	}
+
+	deliveries := []Delivery{}
+	for _, entry := range entries {
+		var delivery Delivery
+		err := json.Unmarshal([]byte(entry), &delivery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal delivery: %v", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
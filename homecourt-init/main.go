@@ -5,15 +5,19 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	ics "github.com/arran4/golang-ical"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
+
+	"homecourt-api/games"
 )
 
 var TeamAbbreviation = map[string]string{
@@ -51,39 +55,75 @@ var TeamAbbreviation = map[string]string{
 	"Washington Wizards":     "WAS",
 }
 
+var (
+	// descriptions look like "NBA Week 9  | Watch the game live ... | Join in #KnicksvWolves"
+	// cup games drop the "NBA" prefix: " Week 4, Emirates NBA Cup, West Group B  | ..."
+	weekPattern    = regexp.MustCompile(`Week (\d+)`)
+	hashtagPattern = regexp.MustCompile(`#(\w+)`)
+)
+
+// parseDescription pulls the season week and the game hashtag out of an event description.
+// week is 0 and hashtag is empty when they can't be found.
+func parseDescription(description string) (week int, hashtag string) {
+	if match := weekPattern.FindStringSubmatch(description); match != nil {
+		week, _ = strconv.Atoi(match[1])
+	}
+	if match := hashtagPattern.FindStringSubmatch(description); match != nil {
+		hashtag = "#" + match[1]
+	}
+	return week, hashtag
+}
+
+// propertyValue returns the value of an event property, or "" if the event doesn't have it.
+func propertyValue(event *ics.VEvent, property ics.ComponentProperty) string {
+	prop := event.GetProperty(property)
+	if prop == nil {
+		return ""
+	}
+	return prop.Value
+}
+
+// fatal logs msg and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
 func main() {
 	err := godotenv.Load(".env.local")
 	icsURL := os.Getenv("CALENDAR_SECRET")
 	if err != nil {
-		log.Fatalf("Error downloading .ics file: %v", err)
+		fatal("error loading .env.local", "err", err)
 	}
 	client := &http.Client{}
 	req, err := http.NewRequest("GET", icsURL, nil)
 	if err != nil {
-		log.Fatalf("error creating request: %v", err)
+		fatal("error creating request", "err", err)
 	}
 	req.Header.Set("User-Agent", "HomecourtScheduler/1.0")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Fatalf("error downloading file: %v", err)
+		fatal("error downloading file", "err", err)
 	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		log.Fatalf("failed to download file: status code %d", resp.StatusCode)
+		fatal("failed to download file", "status", resp.StatusCode)
 	}
 
 	outFile, err := os.Create("homecourt-schedule.ics")
 	if err != nil {
-		log.Fatalf("error creating file: %v", err)
+		fatal("error creating file", "err", err)
 	}
 	defer outFile.Close()
 
 	_, err = io.Copy(outFile, resp.Body)
 	if err != nil {
-		log.Fatalf("error saving file: %v", err)
+		fatal("error saving file", "err", err)
 	}
+	// every game's schedule fields are stamped with when the calendar was downloaded
+	observed := games.Observation{Group: games.GroupSchedule, Source: "calendar", At: time.Now().UTC()}
 	// log.Printf("we did it :D")
 
 	redisClient := redis.NewClient(&redis.Options{
@@ -92,20 +132,26 @@ func main() {
 	ctx := context.Background()
 	_, err = redisClient.Ping(ctx).Result()
 	if err != nil {
-		log.Fatalf("failed to connect to Redis: %v", err)
+		fatal("failed to connect to Redis", "err", err)
+	}
+	manager, err := games.NewGamesManager("localhost:6379")
+	if err != nil {
+		fatal("failed to create games manager", "err", err)
 	}
 
 	content, err := os.ReadFile("homecourt-schedule.ics")
 	if err != nil {
-		log.Fatalf("failed to read file: %v", err)
+		fatal("failed to read file", "err", err)
 	}
 
 	reader := bytes.NewReader(content)
 	calendar, err := ics.ParseCalendar(reader)
 	if err != nil {
-		log.Fatalf("failed to parse calendar: %v", err)
+		fatal("failed to parse calendar", "err", err)
 	}
 
+	// teams with home games in the calendar, reindexed once everything is stored
+	homeTeams := map[string]bool{}
 	for _, event := range calendar.Events() {
 		summary := event.GetProperty(ics.ComponentPropertySummary).Value
 		startTime := event.GetProperty(ics.ComponentPropertyDtStart).Value
@@ -114,7 +160,7 @@ func main() {
 		cleanSummary := strings.TrimSpace(strings.TrimPrefix(summary, "🏀"))
 		teams := strings.Split(cleanSummary, "@")
 		if len(teams) != 2 {
-			log.Printf("unexpected format: %s", summary)
+			slog.Warn("unexpected format", "summary", summary)
 			continue
 		}
 
@@ -122,19 +168,12 @@ func main() {
 		homeTeam := strings.TrimSpace(teams[1])
 
 		datetimeLayout := "20060102T150405Z"
-		date, err := time.Parse(datetimeLayout, startTime)
-		if err != nil {
-			log.Printf("failed to parse date %s: %v", date, err)
-			continue
-		}
-		formattedDate := date.Format("01.02.2006")
-		time, err := time.Parse(datetimeLayout, startTime)
-		formattedTime := time.Format("Jan 2, 2006")
-
+		tipOff, err := time.Parse(datetimeLayout, startTime)
 		if err != nil {
-			log.Printf("Failed to parse start time '%s': %v", startTime, err)
+			slog.Warn("failed to parse start time", "start_time", startTime, "err", err)
 			continue
 		}
+		formattedDate := tipOff.Format("01.02.2006")
 
 		gameID := fmt.Sprintf("%s %s %s", TeamAbbreviation[homeTeam], TeamAbbreviation[awayTeam], formattedDate)
 		gameKey := fmt.Sprintf("game:%s", gameID)
@@ -142,32 +181,74 @@ func main() {
 			"home_team":  TeamAbbreviation[homeTeam],
 			"away_team":  TeamAbbreviation[awayTeam],
 			"venueName":  location,
-			"start_time": formattedTime,
+			"start_time": tipOff.UTC().Format(time.RFC3339),
+		}
+
+		// DTEND, UID and DESCRIPTION are optional as far as the parser is concerned,
+		// so only store what the feed actually gave us
+		if endTime := propertyValue(event, ics.ComponentPropertyDtEnd); endTime != "" {
+			end, err := time.Parse(datetimeLayout, endTime)
+			if err != nil {
+				slog.Warn("failed to parse end time", "end_time", endTime, "err", err)
+			} else {
+				fields["end_time"] = end.Format(time.RFC3339)
+			}
 		}
-		err = redisClient.HSet(ctx, gameKey, fields).Err()
+		if uid := propertyValue(event, ics.ComponentPropertyUniqueId); uid != "" {
+			fields["source_uid"] = uid
+		}
+		week, hashtag := parseDescription(propertyValue(event, ics.ComponentPropertyDescription))
+		if week > 0 {
+			fields["week"] = week
+		}
+		if hashtag != "" {
+			fields["hashtag"] = hashtag
+		}
+
+		// versioned and announced like the feeds' writes, and a tip-off the tickets feed
+		// corrected since the last import stays corrected
+		err = manager.ImportGame(ctx, gameKey, observed, fields)
 		if err != nil {
-			log.Printf("failed to create or update game %s: %v", gameKey, err)
+			slog.Error("failed to create or update game", "game_id", gameID, "err", err)
 			continue
 		}
-		log.Printf("stored game: %s", gameKey)
+		slog.Info("stored game", "game_id", gameID)
 
+		// order the game by the tip-off that was kept, not necessarily the calendar's
+		if game, err := manager.GetGame(ctx, gameID); err == nil {
+			if startTime, err := time.Parse(time.RFC3339, game["start_time"]); err == nil {
+				tipOff = startTime
+			}
+		}
 		upcomingGamesKey := fmt.Sprintf("team:%s:upcoming_home_games", TeamAbbreviation[homeTeam])
-		score := time.Unix()
-		// this might potentially be gameid instead of gamekey....
-		err = redisClient.ZAdd(ctx, upcomingGamesKey, redis.Z{
-			Score:  float64(score),
-			Member: gameID,
-		}).Err()
+		err = manager.AddUpcomingGame(ctx, upcomingGamesKey, gameID, tipOff.Unix())
+		if err != nil {
+			slog.Error("failed to add game to upcoming games list", "game_id", gameID, "err", err)
+			continue
+		}
+		homeTeams[TeamAbbreviation[homeTeam]] = true
+		slog.Info("added game to upcoming games list", "game_id", gameID)
+	}
+
+	// /v1/games and /v1/me/games only find games that are in the indexes, don't leave that
+	// to the janitor's next sweep
+	for team := range homeTeams {
+		indexed, err := manager.ReindexGames(ctx, team)
 		if err != nil {
-			log.Printf("failed to add game to upcoming games list")
+			slog.Error("failed to index games", "team", team, "err", err)
 			continue
 		}
-		log.Printf("added game to upcoming games list")
+		slog.Info("indexed games", "team", team, "games", indexed)
 	}
 
 	// define types for what im storing in redis
 	// parse and store into redis
 	// can consider flushing redis each time we do this
 	// end script
-	log.Printf("finished loading games from schedule")
+	// the API reports the schedule's freshness on /v1/status
+	err = redisClient.HSet(ctx, "sources:last_updated", "schedule", time.Now().UTC().Format(time.RFC3339)).Err()
+	if err != nil {
+		slog.Error("failed to record schedule update", "err", err)
+	}
+	slog.Info("finished loading games from schedule")
 }
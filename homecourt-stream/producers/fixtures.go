@@ -0,0 +1,178 @@
+package producers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Transport makes the requests to the providers once providerTransport has timed and traced
+// them. RecordingTransport and ReplayTransport put fixtures in between.
+var Transport http.RoundTripper = http.DefaultTransport
+
+// query parameters that carry api keys, left out of fixture names
+var secretParams = map[string]bool{"apikey": true, "key": true}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// fixturePath is where the response to req is kept under dir: a directory per provider, and
+// a file named after the request's path and query values, e.g.
+// ticketmaster/events-new-york-nba-new-york-knicks.json.
+func fixturePath(dir string, req *http.Request) (string, error) {
+	f, ok := fetchFrom(req.Context())
+	if !ok {
+		return "", fmt.Errorf("no provider for request to %s", req.URL.Host)
+	}
+
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if !secretParams[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := []string{strings.TrimSuffix(path.Base(req.URL.Path), ".json")}
+	for _, key := range keys {
+		parts = append(parts, query.Get(key))
+	}
+	for i, part := range parts {
+		parts[i] = strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(part), "-"), "-")
+	}
+	return filepath.Join(dir, f.provider, strings.Join(parts, "-")+".json"), nil
+}
+
+// RecordingTransport passes requests on to Next and saves the body of every 200 response
+// as a fixture under Dir. Other responses aren't saved, a fixture is always a success.
+type RecordingTransport struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+func (t RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture, err := fixturePath(t.Dir, req)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(fixture), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(fixture, body, 0o644)
+	}
+	if err != nil {
+		// the response is still good, only the recording is lost
+		slog.ErrorContext(req.Context(), "failed to record fixture", "err", err)
+	} else {
+		slog.DebugContext(req.Context(), "recorded fixture", "fixture", fixture)
+	}
+	return resp, nil
+}
+
+// ReplayTransport answers requests from the fixtures under Dir without calling the
+// providers. Requests without a fixture fail.
+type ReplayTransport struct {
+	Dir string
+}
+
+func (t ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fixture, err := fixturePath(t.Dir, req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(fixture)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture for request: %w", err)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// replaying reports whether requests are answered from fixtures, the providers' api keys
+// aren't needed then.
+func replaying() bool {
+	_, ok := Transport.(ReplayTransport)
+	return ok
+}
+
+// replayers publish a fixture of each provider, and return how many messages they did.
+var replayers = []struct {
+	provider string
+	publish  func(ctx context.Context, channel publisher, body []byte) (int, error)
+}{
+	{"ticketmaster", func(ctx context.Context, channel publisher, body []byte) (int, error) {
+		messages, err := publishEvents(ctx, channel, body)
+		return len(messages), err
+	}},
+	{"oddsblaze", func(ctx context.Context, channel publisher, body []byte) (int, error) {
+		messages, err := publishSlate(ctx, channel, body)
+		return len(messages), err
+	}},
+	{"espn", func(ctx context.Context, channel publisher, body []byte) (int, error) {
+		messages, err := publishScoreboard(ctx, channel, body)
+		return len(messages), err
+	}},
+}
+
+// Replay publishes every fixture under dir as if its provider had just returned it,
+// ticketmaster's first, then oddsblaze's and espn's. A fixture that can't be published is
+// logged and skipped.
+func Replay(channel *amqp.Channel, dir string) error {
+	replayed := 0
+	for _, replayer := range replayers {
+		fixtures, err := filepath.Glob(filepath.Join(dir, replayer.provider, "*.json"))
+		if err != nil {
+			return err
+		}
+		for _, fixture := range fixtures {
+			ctx, span := startFetch(replayer.provider, attribute.String("fixture", fixture))
+			body, err := os.ReadFile(fixture)
+			var published int
+			if err == nil {
+				published, err = replayer.publish(ctx, channel, body)
+			}
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to replay fixture", "provider", replayer.provider, "fixture", fixture, "err", err)
+			} else {
+				slog.InfoContext(ctx, "replayed fixture", "provider", replayer.provider, "fixture", fixture, "messages", published)
+				replayed++
+			}
+			endSpan(span, err)
+		}
+	}
+	if replayed == 0 {
+		return fmt.Errorf("no fixtures replayed from %s", dir)
+	}
+	return nil
+}
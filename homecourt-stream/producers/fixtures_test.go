@@ -0,0 +1,171 @@
+package producers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const fixturesDir = "../fixtures"
+
+// fakeChannel keeps what's published instead of sending it to RabbitMQ.
+type fakeChannel struct {
+	published []amqp.Publishing
+	keys      []string
+}
+
+func (c *fakeChannel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	c.published = append(c.published, msg)
+	c.keys = append(c.keys, key)
+	return nil
+}
+
+// replayFixtures answers requests from the committed fixtures for the rest of the test.
+func replayFixtures(t *testing.T) {
+	t.Helper()
+	previous := Transport
+	Transport = ReplayTransport{Dir: fixturesDir}
+	t.Cleanup(func() { Transport = previous })
+}
+
+func TestFixturePath(t *testing.T) {
+	tests := []struct {
+		provider string
+		url      string
+		want     string
+	}{
+		{
+			"ticketmaster",
+			"https://app.ticketmaster.com/discovery/v2/events.json?apikey=secret&classificationName=NBA&city=New+York&keyword=New+York+Knicks",
+			"ticketmaster/events-new-york-nba-new-york-knicks.json",
+		},
+		{
+			"oddsblaze",
+			"https://data.oddsblaze.com/v1/odds/espn_bet_nba.json?key=secret&market=Moneyline&live=false",
+			"oddsblaze/espn-bet-nba-false-moneyline.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(withFetch(context.Background(), tt.provider), http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := fixturePath(fixturesDir, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(fixturesDir, tt.want); got != want {
+				t.Errorf("fixturePath = %s, want %s", got, want)
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/events.json", nil)
+	if _, err := fixturePath(fixturesDir, req); err == nil {
+		t.Error("fixturePath of a request outside a fetch succeeded, want an error")
+	}
+}
+
+func TestReplayTransport(t *testing.T) {
+	transport := ReplayTransport{Dir: fixturesDir}
+	ctx := withFetch(context.Background(), "oddsblaze")
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://data.oddsblaze.com/v1/odds/espn_bet_nba.json?key=other&live=false&market=Moneyline", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join(fixturesDir, "oddsblaze", "espn-bet-nba-false-moneyline.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != string(want) {
+		t.Errorf("got %d with %d bytes, want 200 with the fixture's %d", resp.StatusCode, len(body), len(want))
+	}
+
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "https://data.oddsblaze.com/v1/odds/espn_bet_nfl.json?live=false&market=Moneyline", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Error("request without a fixture succeeded, want an error")
+	}
+}
+
+func TestPublishTicketsFromFixture(t *testing.T) {
+	replayFixtures(t)
+	client := &http.Client{Timeout: time.Second, Transport: providerTransport{provider: "ticketmaster"}}
+	channel := &fakeChannel{}
+
+	ctx, span := startFetch("ticketmaster")
+	defer span.End()
+	// the request HandleTickets makes for the Knicks, without a key
+	apiURL := "https://app.ticketmaster.com/discovery/v2/events.json?apikey=&classificationName=NBA&city=New+York&keyword=New+York+Knicks"
+	messages, err := publishTickets(ctx, channel, client, apiURL, "New York Knicks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 20 || len(channel.published) != 20 {
+		t.Fatalf("got %d messages and %d published, want 20 of each", len(messages), len(channel.published))
+	}
+	first := messages[0]
+	if first.EventName != "New York Knicks vs. Washington Wizards" || first.StartDateTime != "2024-11-19T00:30:00Z" ||
+		first.MinTicketPrice != 75 || first.VenueName != "Madison Square Garden" {
+		t.Errorf("first message = %+v", first)
+	}
+	for i, published := range channel.published {
+		if channel.keys[i] != "tickets" || published.Headers[sourceHeader] != "ticketmaster" || published.Timestamp.IsZero() {
+			t.Fatalf("message %d published to %s with headers %v at %s", i, channel.keys[i], published.Headers, published.Timestamp)
+		}
+	}
+}
+
+func TestPublishOddsFromFixture(t *testing.T) {
+	replayFixtures(t)
+	client := &http.Client{Timeout: time.Second, Transport: providerTransport{provider: "oddsblaze"}}
+	channel := &fakeChannel{}
+
+	ctx, span := startFetch("oddsblaze")
+	defer span.End()
+	messages, err := publishOdds(ctx, channel, client, "https://data.oddsblaze.com/v1/odds/espn_bet_nba.json?key=&market=Moneyline&live=false")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 32 || len(channel.published) != 32 {
+		t.Fatalf("got %d messages and %d published, want 32 of each", len(messages), len(channel.published))
+	}
+	first := messages[0]
+	if first.AwayTeam != "Atlanta Hawks" || first.HomeTeam != "Washington Wizards" ||
+		first.BettingPrices["Atlanta Hawks"] != "-190" || first.BettingPrices["Washington Wizards"] != "+155" {
+		t.Errorf("first message = %+v", first)
+	}
+	for i, published := range channel.published {
+		if channel.keys[i] != "odds" || published.Headers[sourceHeader] != "oddsblaze" {
+			t.Fatalf("message %d published to %s with headers %v", i, channel.keys[i], published.Headers)
+		}
+	}
+}
+
+func TestPublishSlateRejectsGarbage(t *testing.T) {
+	channel := &fakeChannel{}
+	if _, err := publishSlate(context.Background(), channel, []byte("<html>")); err == nil {
+		t.Error("publishSlate of a non-JSON body succeeded, want an error")
+	}
+	if _, err := publishEvents(context.Background(), channel, []byte("<html>")); err == nil {
+		t.Error("publishEvents of a non-JSON body succeeded, want an error")
+	}
+	if len(channel.published) != 0 {
+		t.Errorf("published %d messages, want none", len(channel.published))
+	}
+}
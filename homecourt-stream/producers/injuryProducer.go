@@ -1,9 +1,8 @@
 package producers
 
 import (
-	"log"
-
 	"fmt"
+	"log/slog"
 
 	"github.com/playwright-community/playwright-go"
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -28,39 +27,39 @@ func HandleInjuries(channel *amqp.Channel) {
 	printInjuryReport()
 	// pdf to text through gosseract
 	// text to json through openai
-	publishMessage(channel, "homecourt_exchange", "injuries", nil)
+	publishMessage(newCorrelationContext(), channel, "homecourt_exchange", "injuries", nil)
 }
 
 func printInjuryReport() {
 	pw, err := playwright.Run()
 
 	if err != nil {
-		log.Fatalf("could not start Playwright: %v", err)
+		fatal("could not start Playwright", "err", err)
 	}
 	defer pw.Stop()
-	log.Printf("started playwright")
+	slog.Info("started playwright")
 
 	browser, err := pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
 		Headless: playwright.Bool(false),
 	})
 	if err != nil {
-		log.Fatalf("could not launch browser: %v", err)
+		fatal("could not launch browser", "err", err)
 	}
 	defer browser.Close()
-	log.Printf("launched browser")
+	slog.Info("launched browser")
 
 	context, err := browser.NewContext()
 	if err != nil {
-		log.Fatalf("could not create browser context: %v", err)
+		fatal("could not create browser context", "err", err)
 	}
 	defer context.Close()
-	log.Printf("created browser context")
+	slog.Info("created browser context")
 
 	page, err := context.NewPage()
 	if err != nil {
-		log.Fatalf("could not create page: %v", err)
+		fatal("could not create page", "err", err)
 	}
-	log.Printf("created page")
+	slog.Info("created page")
 
 	// team := "celtics"
 	// url := "https://www.nba.com/" + team + "/roster"
@@ -69,9 +68,9 @@ func printInjuryReport() {
 	if _, err := page.Goto(url, playwright.PageGotoOptions{
 		WaitUntil: playwright.WaitUntilStateLoad,
 	}); err != nil {
-		log.Fatalf("could not navigate to %s: %v", url, err)
+		fatal("could not navigate", "url", url, "err", err)
 	}
-	log.Printf("went to %s", url)
+	slog.Info("went to page", "url", url)
 
 	// Parameters for DOM stability
 	const (
@@ -111,15 +110,15 @@ func printInjuryReport() {
 	`, stabilityDuration, maxTimeout, checkInterval)
 
 	if _, err := page.WaitForFunction(script, nil); err != nil {
-		log.Fatalf("DOM did not stabilize: %v", err)
+		fatal("DOM did not stabilize", "err", err)
 	}
 
-	log.Println("DOM has stabilized; proceeding to capture content.")
+	slog.Info("DOM has stabilized, proceeding to capture content")
 
 	if _, err := page.PDF(playwright.PagePdfOptions{
 		Path: playwright.String("output.pdf"),
 	}); err != nil {
-		log.Fatalf("Failed to generate PDF: %v", err)
+		fatal("failed to generate PDF", "err", err)
 	}
 
 }
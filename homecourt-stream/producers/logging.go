@@ -0,0 +1,60 @@
+package producers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// correlationHeader carries the correlation ID to the receiver in homecourt-api, which logs
+// it with everything it does with the message and stores it with the game it updates.
+const correlationHeader = "correlation_id"
+
+type contextKey struct{}
+
+// newCorrelationContext starts the trail of one provider fetch. Every message published
+// from what the fetch returned carries the same ID.
+func newCorrelationContext() context.Context {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return context.WithValue(context.Background(), contextKey{}, hex.EncodeToString(b))
+}
+
+func correlationID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// LogHandler adds the correlation and trace IDs of the context passed to slog's ...Context functions
+// to every record.
+type LogHandler struct {
+	slog.Handler
+}
+
+func (h LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := correlationID(ctx); id != "" {
+		record.AddAttrs(slog.String("correlation_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return LogHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h LogHandler) WithGroup(name string) slog.Handler {
+	return LogHandler{h.Handler.WithGroup(name)}
+}
+
+// fatal logs msg and exits, for configuration a producer can't run without.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,71 @@
+package producers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	messagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "homecourt_messages_published_total",
+		Help: "Messages published to homecourt_exchange, by queue and outcome (ok or error).",
+	}, []string{"queue", "outcome"})
+	providerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "homecourt_provider_request_duration_seconds",
+		Help:    "Requests to the data providers, by provider.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"provider"})
+	providerResponses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "homecourt_provider_responses_total",
+		Help: "Responses from the data providers, by provider and status code (error when there was no response).",
+	}, []string{"provider", "code"})
+	providerRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "homecourt_provider_rate_limited_total",
+		Help: "Requests the data providers turned away with a 429, by provider.",
+	}, []string{"provider"})
+)
+
+// providerTransport records latency and status codes of every request to provider, and
+// traces it under the span of the request's context.
+type providerTransport struct {
+	provider string
+}
+
+func (t providerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the url is left out, it carries the api keys
+	_, span := tracer.Start(req.Context(), req.Method+" "+t.provider,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := Transport.RoundTrip(req)
+	providerDuration.WithLabelValues(t.provider).Observe(time.Since(start).Seconds())
+	if err != nil {
+		providerResponses.WithLabelValues(t.provider, "error").Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	providerResponses.WithLabelValues(t.provider, strconv.Itoa(resp.StatusCode)).Inc()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		providerRateLimited.WithLabelValues(t.provider).Inc()
+	}
+	return resp, nil
+}
@@ -1,10 +1,10 @@
 package producers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -67,17 +67,17 @@ func parseOddsJSON(jsonData []byte) (*OddsResponse, error) {
 }
 
 // extractOddsMessages processes the OddsResponse and returns a slice of OddsMessage.
-func extractOddsMessages(response *OddsResponse) []OddsMessage {
+func extractOddsMessages(ctx context.Context, response *OddsResponse) []OddsMessage {
 	var messages []OddsMessage
 
 	for _, game := range response.Games {
-		// Parse the game start time
-		gameTime, err := time.Parse("2006-01-02T15:04:05", game.Start)
+		// oddsblaze sends start times without a zone, but they're UTC
+		gameTime, err := time.ParseInLocation("2006-01-02T15:04:05", game.Start, time.UTC)
 		if err != nil {
-			log.Printf("Error parsing game start time: %v", err)
+			slog.WarnContext(ctx, "error parsing game start time", "start", game.Start, "err", err)
 			continue
 		}
-		formattedStartTime := gameTime.Format(time.RFC3339)
+		formattedStartTime := gameTime.UTC().Format(time.RFC3339)
 
 		// Initialize the OddsMessage
 		message := OddsMessage{
@@ -99,7 +99,7 @@ func extractOddsMessages(response *OddsResponse) []OddsMessage {
 				}
 			}
 		} else {
-			log.Printf("No sportsbooks found for game: %s vs %s", message.AwayTeam, message.HomeTeam)
+			slog.DebugContext(ctx, "no sportsbooks found for game", "away_team", message.AwayTeam, "home_team", message.HomeTeam)
 		}
 
 		messages = append(messages, message)
@@ -108,51 +108,69 @@ func extractOddsMessages(response *OddsResponse) []OddsMessage {
 	return messages
 }
 
+// oddsSlate is the scheduler's one oddsblaze target, every game comes in a single response
+const oddsSlate = "espn_bet_nba"
+
 func HandleOdds(channel *amqp.Channel) {
 	apiKey := os.Getenv("ODDSBLAZEKEY")
-	if apiKey == "" {
-		log.Fatal("ODDSBLAZEKEY hasn't been set")
+	if apiKey == "" && !replaying() {
+		fatal("ODDSBLAZEKEY hasn't been set")
 	}
 
-	ticker := time.NewTicker(10 * time.Second) // rate limit is 10 api calls per minute
-	defer ticker.Stop()
-
 	client := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout:   10 * time.Second,
+		Transport: providerTransport{provider: "oddsblaze"},
 	}
 
 	apiURL := fmt.Sprintf("https://data.oddsblaze.com/v1/odds/espn_bet_nba.json?key=%s&market=Moneyline&live=false", apiKey)
 
-	for range ticker.C {
-		resp, err := client.Get(apiURL)
-		if err != nil {
-			log.Printf("failed to get odds from oddsblaze: %v", err)
-			continue
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			log.Printf("unexpected status code %d for oddsblaze api", resp.StatusCode)
+	// the slate is fetched more often as its next game gets closer, or when lines are moving
+	for {
+		_, wait := Scheduler.Next("oddsblaze", []string{oddsSlate})
+		if wait > 0 {
+			time.Sleep(wait)
 			continue
 		}
 
-		bodyBytes, err := io.ReadAll(resp.Body)
+		ctx, span := startFetch("oddsblaze")
+		oddsMessages, err := publishOdds(ctx, channel, client, apiURL)
 		if err != nil {
-			log.Printf("can't read body from oddsblaze api response: %v", err)
-			continue
+			slog.ErrorContext(ctx, "failed to fetch odds", "provider", "oddsblaze", "err", err)
+			holdOff(ctx, "oddsblaze", err)
+			Scheduler.Failed("oddsblaze", oddsSlate)
+		} else {
+			Scheduler.Observed("oddsblaze", oddsSlate, oddsGames(oddsMessages))
 		}
-		resp.Body.Close()
+		endSpan(span, err)
+	}
+}
 
-		oddsResponse, err := parseOddsJSON(bodyBytes)
-		if err != nil {
-			log.Printf("error unmarshalling odds: %v", err)
-			continue
-		}
+// publishOdds fetches the slate from oddsblaze and publishes the odds of every game on it.
+// It returns what it published.
+func publishOdds(ctx context.Context, channel publisher, client *http.Client, apiURL string) ([]OddsMessage, error) {
+	bodyBytes, err := getBody(ctx, client, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get odds from oddsblaze: %w", err)
+	}
 
-		oddsMessages := extractOddsMessages(oddsResponse)
-		for _, message := range oddsMessages {
-			publishMessage(channel, "homecourt_exchange", "odds", message)
-		}
+	oddsMessages, err := publishSlate(ctx, channel, bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "fetched odds", "provider", "oddsblaze", "games", len(oddsMessages))
+	return oddsMessages, nil
+}
 
+// publishSlate publishes the odds of every game in an oddsblaze response.
+func publishSlate(ctx context.Context, channel publisher, body []byte) ([]OddsMessage, error) {
+	oddsResponse, err := parseOddsJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling odds: %w", err)
 	}
 
+	oddsMessages := extractOddsMessages(ctx, oddsResponse)
+	for _, message := range oddsMessages {
+		publishMessage(ctx, channel, "homecourt_exchange", "odds", message)
+	}
+	return oddsMessages, nil
 }
@@ -1,34 +1,90 @@
 package producers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func publishMessage(channel *amqp.Channel, exchange, routingKey string, data interface{}) {
+// sourceHeader names the provider a message's data was fetched from. The receiver stamps
+// the fields it writes with it, and with the message timestamp as their observed_at.
+const sourceHeader = "source"
+
+// publisher is what messages are published through, an *amqp.Channel outside of tests.
+type publisher interface {
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+type fetchKey struct{}
+
+// fetch is the provider and time of the fetch a message is published from.
+type fetch struct {
+	provider string
+	at       time.Time
+}
+
+func withFetch(ctx context.Context, provider string) context.Context {
+	return context.WithValue(ctx, fetchKey{}, fetch{provider: provider, at: time.Now()})
+}
+
+func fetchFrom(ctx context.Context) (fetch, bool) {
+	f, ok := ctx.Value(fetchKey{}).(fetch)
+	return f, ok
+}
+
+func publishMessage(ctx context.Context, channel publisher, exchange, routingKey string, data interface{}) {
+	ctx, span := tracer.Start(ctx, routingKey+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", routingKey),
+		),
+	)
+	defer span.End()
+
 	body, err := json.Marshal(data)
 	if err != nil {
-		log.Printf("error marshalling data for %s: %v", routingKey, err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "error marshalling message", "queue", routingKey, "err", err)
 		return
 	}
 
-	err = channel.Publish(
+	headers := amqp.Table{correlationHeader: correlationID(ctx)}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	publishing := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+		Headers:      headers,
+	}
+	if f, ok := fetchFrom(ctx); ok {
+		headers[sourceHeader] = f.provider
+		publishing.Timestamp = f.at
+	}
+
+	err = channel.PublishWithContext(
+		ctx,
 		exchange,   // exchange
 		routingKey, // routing key
 		false,      // mandatory
 		false,      // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: amqp.Persistent,
-		},
+		publishing,
 	)
 	if err != nil {
-		log.Printf("error publishing message to %s: %v", routingKey, err)
+		messagesPublished.WithLabelValues(routingKey, "error").Inc()
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "error publishing message", "queue", routingKey, "err", err)
 		return
 	}
+	messagesPublished.WithLabelValues(routingKey, "ok").Inc()
 
-	log.Printf("published message to %s: %s", routingKey, string(body))
+	slog.DebugContext(ctx, "published message", "queue", routingKey, "body", json.RawMessage(body))
 }
@@ -0,0 +1,190 @@
+package producers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ScoreboardResponse is the subset of ESPN's NBA scoreboard we care about.
+type ScoreboardResponse struct {
+	Events []ScoreboardEvent `json:"events"`
+}
+
+// ScoreboardEvent represents a single game on the scoreboard.
+type ScoreboardEvent struct {
+	ID           string        `json:"id"`
+	Date         string        `json:"date"` // e.g. "2024-11-28T00:30Z"
+	Name         string        `json:"name"`
+	Competitions []Competition `json:"competitions"`
+}
+
+// Competition holds both sides of a game and its status.
+type Competition struct {
+	Competitors []Competitor `json:"competitors"`
+	Status      EventStatus  `json:"status"`
+}
+
+// Competitor is one team in a competition.
+type Competitor struct {
+	HomeAway string `json:"homeAway"` // "home" or "away"
+	Score    string `json:"score"`
+	Team     struct {
+		DisplayName string `json:"displayName"`
+	} `json:"team"`
+}
+
+// EventStatus describes where a game is at.
+type EventStatus struct {
+	Type struct {
+		Name      string `json:"name"` // e.g. "STATUS_FINAL"
+		Completed bool   `json:"completed"`
+	} `json:"type"`
+}
+
+// ResultsMessage is the simplified message to be published to RabbitMQ.
+type ResultsMessage struct {
+	AwayTeam  string `json:"away_team"`
+	HomeTeam  string `json:"home_team"`
+	StartTime string `json:"start_time"`
+	Status    string `json:"status"` // scheduled, live, final or postponed
+	AwayScore int    `json:"away_score"`
+	HomeScore int    `json:"home_score"`
+}
+
+// espn status names mapped onto the statuses homecourt tracks
+var gameStatuses = map[string]string{
+	"STATUS_SCHEDULED":   "scheduled",
+	"STATUS_IN_PROGRESS": "live",
+	"STATUS_HALFTIME":    "live",
+	"STATUS_END_PERIOD":  "live",
+	"STATUS_OVERTIME":    "live",
+	"STATUS_FINAL":       "final",
+	"STATUS_FINAL_OT":    "final",
+	"STATUS_POSTPONED":   "postponed",
+	"STATUS_CANCELED":    "postponed",
+	"STATUS_DELAYED":     "scheduled",
+}
+
+func parseScoreboardJSON(jsonData []byte) (*ScoreboardResponse, error) {
+	var response ScoreboardResponse
+	err := json.Unmarshal(jsonData, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// extractResultsMessages processes the ScoreboardResponse and returns a slice of ResultsMessage.
+func extractResultsMessages(ctx context.Context, response *ScoreboardResponse) []ResultsMessage {
+	var messages []ResultsMessage
+
+	for _, event := range response.Events {
+		if len(event.Competitions) == 0 {
+			slog.WarnContext(ctx, "no competitions found for event", "event", event.Name)
+			continue
+		}
+		competition := event.Competitions[0]
+
+		startTime, err := time.Parse("2006-01-02T15:04Z", event.Date)
+		if err != nil {
+			slog.WarnContext(ctx, "error parsing event start time", "event", event.Name, "err", err)
+			continue
+		}
+
+		status, ok := gameStatuses[competition.Status.Type.Name]
+		if !ok {
+			slog.WarnContext(ctx, "unknown status for event", "status", competition.Status.Type.Name, "event", event.Name)
+			continue
+		}
+
+		message := ResultsMessage{
+			StartTime: startTime.UTC().Format(time.RFC3339),
+			Status:    status,
+		}
+		for _, competitor := range competition.Competitors {
+			// scores are empty strings before tip-off
+			score, _ := strconv.Atoi(competitor.Score)
+			switch competitor.HomeAway {
+			case "home":
+				message.HomeTeam = competitor.Team.DisplayName
+				message.HomeScore = score
+			case "away":
+				message.AwayTeam = competitor.Team.DisplayName
+				message.AwayScore = score
+			}
+		}
+		if message.HomeTeam == "" || message.AwayTeam == "" {
+			slog.WarnContext(ctx, "missing home or away team for event", "event", event.Name)
+			continue
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages
+}
+
+func HandleResults(channel *amqp.Channel) {
+	ticker := time.NewTicker(60 * time.Second) // scores only matter once games are final
+	defer ticker.Stop()
+
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: providerTransport{provider: "espn"},
+	}
+
+	for range ticker.C {
+		// games that tip off late in the evening US time finish on the next UTC day,
+		// so look at yesterday's scoreboard as well as today's
+		now := time.Now().UTC()
+		for _, day := range []time.Time{now.Add(-24 * time.Hour), now} {
+			apiURL := fmt.Sprintf("https://site.api.espn.com/apis/site/v2/sports/basketball/nba/scoreboard?dates=%s", day.Format("20060102"))
+
+			date := day.Format("2006-01-02")
+			ctx, span := startFetch("espn", attribute.String("date", date))
+			err := publishResults(ctx, channel, client, apiURL)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to fetch scoreboard", "provider", "espn", "date", date, "err", err)
+			}
+			endSpan(span, err)
+		}
+	}
+}
+
+// publishResults fetches a day's scoreboard from espn and publishes the status and score of
+// every game on it.
+func publishResults(ctx context.Context, channel publisher, client *http.Client, apiURL string) error {
+	bodyBytes, err := getBody(ctx, client, apiURL)
+	if err != nil {
+		return fmt.Errorf("failed to get scoreboard from espn: %w", err)
+	}
+
+	resultsMessages, err := publishScoreboard(ctx, channel, bodyBytes)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "fetched scoreboard", "provider", "espn", "games", len(resultsMessages))
+	return nil
+}
+
+// publishScoreboard publishes the status and score of every game in an espn scoreboard.
+func publishScoreboard(ctx context.Context, channel publisher, body []byte) ([]ResultsMessage, error) {
+	scoreboard, err := parseScoreboardJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling scoreboard: %w", err)
+	}
+
+	resultsMessages := extractResultsMessages(ctx, scoreboard)
+	for _, message := range resultsMessages {
+		publishMessage(ctx, channel, "homecourt_exchange", "results", message)
+	}
+	return resultsMessages, nil
+}
@@ -0,0 +1,79 @@
+package producers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"homecourt-stream/scheduler"
+)
+
+// Scheduler decides when ticketmaster and oddsblaze are fetched, and for which team.
+var Scheduler *scheduler.Scheduler
+
+// how long a provider is left alone after a 429 that doesn't say for how long
+const defaultRetryAfter = time.Minute
+
+// holdOff tells the scheduler to leave provider alone when err is it turning us away: a 429,
+// or a 503 with a Retry-After.
+func holdOff(ctx context.Context, provider string, err error) {
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) {
+		return
+	}
+	retryAfter := statusErr.retryAfter
+	switch statusErr.code {
+	case http.StatusTooManyRequests:
+		if retryAfter == 0 {
+			retryAfter = defaultRetryAfter
+		}
+	case http.StatusServiceUnavailable:
+		if retryAfter == 0 {
+			return
+		}
+	default:
+		return
+	}
+	slog.WarnContext(ctx, "provider asked to retry later", "provider", provider, "status", statusErr.code, "retry_after", retryAfter.String())
+	Scheduler.RetryAfter(provider, retryAfter)
+}
+
+// ticketGames are the games of ticket messages, priced by their lowest ticket.
+func ticketGames(messages []TicketMessage) []scheduler.Game {
+	var games []scheduler.Game
+	for _, message := range messages {
+		tipOff, _ := time.Parse(time.RFC3339, message.StartDateTime)
+		games = append(games, scheduler.Game{Key: message.EventName + " " + message.StartDateTime, TipOff: tipOff, Price: message.MinTicketPrice})
+	}
+	return games
+}
+
+// oddsGames are the games of odds messages, priced by the home team's implied win
+// probability so a move from -110 to +105 reads as the small change it is.
+func oddsGames(messages []OddsMessage) []scheduler.Game {
+	var games []scheduler.Game
+	for _, message := range messages {
+		tipOff, _ := time.Parse(time.RFC3339, message.StartTime)
+		games = append(games, scheduler.Game{
+			Key:    message.HomeTeam + " " + message.AwayTeam + " " + message.StartTime,
+			TipOff: tipOff,
+			Price:  impliedProbability(message.BettingPrices[message.HomeTeam]),
+		})
+	}
+	return games
+}
+
+// impliedProbability of American odds like "-150" or "+135", 0 when odds isn't valid.
+func impliedProbability(odds string) float64 {
+	n, err := strconv.Atoi(odds)
+	if err != nil || n == 0 {
+		return 0
+	}
+	if n > 0 {
+		return 100 / float64(n+100)
+	}
+	return float64(-n) / float64(-n+100)
+}
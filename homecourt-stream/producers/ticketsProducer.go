@@ -1,16 +1,17 @@
 package producers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type TicketmasterResponse struct {
@@ -36,7 +37,8 @@ type Event struct {
 
 // Dates contains date information
 type Dates struct {
-	Start Start `json:"start"`
+	Start    Start  `json:"start"`
+	Timezone string `json:"timezone"` // IANA zone of the venue, e.g. "America/New_York"
 }
 
 // Start contains the start time information
@@ -78,21 +80,20 @@ type TeamInfo struct {
 }
 
 func HandleTickets(channel *amqp.Channel) {
+	// fixtures are stored without the key, so replaying doesn't need one
 	apiKey := os.Getenv("TICKETMASTERKEY")
-	if apiKey == "" {
-		log.Fatal("TICKETMASTERKEY hasn't been set")
+	if apiKey == "" && !replaying() {
+		fatal("TICKETMASTERKEY hasn't been set")
 	}
 
 	apiSecret := os.Getenv("TICKETMASTERSECRET")
-	if apiSecret == "" {
-		log.Fatal("TICKETMASTERSECRET hasn't been set")
+	if apiSecret == "" && !replaying() {
+		fatal("TICKETMASTERSECRET hasn't been set")
 	}
 
-	ticker := time.NewTicker(20 * time.Second) // rate limit is 3.5 api calls per minute
-	defer ticker.Stop()
-
 	client := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout:   10 * time.Second,
+		Transport: providerTransport{provider: "ticketmaster"},
 	}
 
 	var nbaTeams = []TeamInfo{
@@ -128,62 +129,71 @@ func HandleTickets(channel *amqp.Channel) {
 		{TeamName: "Washington Wizards", City: "Washington"},
 	}
 
-	teamIndex := 0
-	teamCount := len(nbaTeams)
-
-	// need to do some kind of ordering here so that we know which games to look up
-	for range ticker.C {
+	cities := map[string]string{}
+	teamNames := make([]string, len(nbaTeams))
+	for i, teamInfo := range nbaTeams {
+		cities[teamInfo.TeamName] = teamInfo.City
+		teamNames[i] = teamInfo.TeamName
+	}
 
-		teamInfo := nbaTeams[teamIndex]
-		teamName := teamInfo.TeamName
-		cityName := teamInfo.City
+	// teams with games coming up, or with prices on the move, are fetched more often
+	for {
+		teamName, wait := Scheduler.Next("ticketmaster", teamNames)
+		if wait > 0 {
+			time.Sleep(wait)
+			continue
+		}
 
 		// URL-encode the parameters
-		encodedCity := url.QueryEscape(cityName)
+		encodedCity := url.QueryEscape(cities[teamName])
 		encodedTeam := url.QueryEscape(teamName)
 
 		// Build the API URL with the current city and team name
 		apiURL := fmt.Sprintf("https://app.ticketmaster.com/discovery/v2/events.json?apikey=%s&classificationName=NBA&city=%s&keyword=%s", apiKey, encodedCity, encodedTeam)
-		// log.Printf(apiURL)
-		resp, err := client.Get(apiURL)
+		ctx, span := startFetch("ticketmaster", attribute.String("team", teamName))
+		ticketMessages, err := publishTickets(ctx, channel, client, apiURL, teamName)
 		if err != nil {
-			log.Printf("failed to get events from ticketmaster: %v", err)
-			teamIndex = (teamIndex + 1) % teamCount
-			continue
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			log.Printf("unexpected status code %d from ticketmaster api", resp.StatusCode)
-			resp.Body.Close()
-			teamIndex = (teamIndex + 1) % teamCount
-			continue
+			slog.ErrorContext(ctx, "failed to fetch events", "provider", "ticketmaster", "team", teamName, "err", err)
+			holdOff(ctx, "ticketmaster", err)
+			Scheduler.Failed("ticketmaster", teamName)
+		} else {
+			Scheduler.Observed("ticketmaster", teamName, ticketGames(ticketMessages))
 		}
+		endSpan(span, err)
+	}
+}
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			log.Printf("can't read body from ticketmaster api response: %v", err)
-			teamIndex = (teamIndex + 1) % teamCount
-			continue
-		}
+// publishTickets fetches teamName's events from ticketmaster and publishes the lowest
+// price of each. It returns what it published.
+func publishTickets(ctx context.Context, channel publisher, client *http.Client, apiURL, teamName string) ([]TicketMessage, error) {
+	bodyBytes, err := getBody(ctx, client, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events from ticketmaster: %w", err)
+	}
 
-		ticketmasterResponse, err := parseTicketmasterJSON(bodyBytes)
-		if err != nil {
-			log.Printf("error unmarshalling ticketmaster response: %v", err)
-			teamIndex = (teamIndex + 1) % teamCount
-			continue
-		}
+	ticketMessages, err := publishEvents(ctx, channel, bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "fetched events", "provider", "ticketmaster", "team", teamName, "events", len(ticketMessages))
+	return ticketMessages, nil
+}
 
-		ticketMessages := extractTicketMedssages(ticketmasterResponse)
-		for _, message := range ticketMessages {
-			publishMessage(channel, "homecourt_exchange", "tickets", message)
-		}
-		teamIndex = (teamIndex + 1) % teamCount
+// publishEvents publishes the lowest price of every event in a ticketmaster response.
+func publishEvents(ctx context.Context, channel publisher, body []byte) ([]TicketMessage, error) {
+	ticketmasterResponse, err := parseTicketmasterJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling ticketmaster response: %w", err)
+	}
 
+	ticketMessages := extractTicketMedssages(ctx, ticketmasterResponse)
+	for _, message := range ticketMessages {
+		publishMessage(ctx, channel, "homecourt_exchange", "tickets", message)
 	}
+	return ticketMessages, nil
 }
 
-func extractTicketMedssages(response *TicketmasterResponse) []TicketMessage {
+func extractTicketMedssages(ctx context.Context, response *TicketmasterResponse) []TicketMessage {
 	var messages []TicketMessage
 
 	for _, event := range response.Embedded.Events {
@@ -192,14 +202,13 @@ func extractTicketMedssages(response *TicketmasterResponse) []TicketMessage {
 		// Extract event ID
 		message.EventName = event.Name
 
-		// Extract start date and time
-		if event.Dates.Start.DateTime != "" {
-			message.StartDateTime = event.Dates.Start.DateTime
-		} else if event.Dates.Start.LocalDate != "" && event.Dates.Start.LocalTime != "" {
-			message.StartDateTime = fmt.Sprintf("%sT%s", event.Dates.Start.LocalDate, event.Dates.Start.LocalTime)
-		} else {
-			message.StartDateTime = ""
+		// Extract start date and time as a UTC instant
+		startDateTime, err := eventStartTime(event.Dates)
+		if err != nil {
+			slog.WarnContext(ctx, "skipping event", "event", event.Name, "err", err)
+			continue
 		}
+		message.StartDateTime = startDateTime.Format(time.RFC3339)
 
 		// Extract minimum ticket price
 		if len(event.PriceRanges) > 0 {
@@ -223,6 +232,33 @@ func extractTicketMedssages(response *TicketmasterResponse) []TicketMessage {
 	return messages
 }
 
+// eventStartTime returns the UTC start of an event. dateTime is already UTC; when it's missing
+// the local date and time are interpreted in the venue's timezone.
+func eventStartTime(dates Dates) (time.Time, error) {
+	if dates.Start.DateTime != "" {
+		startTime, err := time.Parse(time.RFC3339, dates.Start.DateTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid dateTime %q: %v", dates.Start.DateTime, err)
+		}
+		return startTime.UTC(), nil
+	}
+
+	if dates.Start.LocalDate == "" || dates.Start.LocalTime == "" {
+		return time.Time{}, fmt.Errorf("no start time")
+	}
+
+	loc, err := time.LoadLocation(dates.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown venue timezone %q: %v", dates.Timezone, err)
+	}
+	localStart := fmt.Sprintf("%sT%s", dates.Start.LocalDate, dates.Start.LocalTime)
+	startTime, err := time.ParseInLocation("2006-01-02T15:04:05", localStart, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid local start %q: %v", localStart, err)
+	}
+	return startTime.UTC(), nil
+}
+
 func parseTicketmasterJSON(jsonData []byte) (*TicketmasterResponse, error) {
 	var response TicketmasterResponse
 	err := json.Unmarshal(jsonData, &response)
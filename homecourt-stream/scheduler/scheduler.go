@@ -0,0 +1,321 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultQuotas are the calls per minute each provider allows.
+var DefaultQuotas = map[string]float64{
+	"ticketmaster": 3.5,
+	"oddsblaze":    10,
+}
+
+// Cadence is how often a target is refreshed, by how far away its next tip-off is.
+type Cadence struct {
+	Near time.Duration // tip-off within 6 hours, or a game under way
+	Day  time.Duration // within 2 days
+	Week time.Duration // within 2 weeks
+	Far  time.Duration // later, or no game known
+}
+
+// DefaultCadences are per provider. A ticketmaster target is one team's events, oddsblaze
+// has a single target for the whole slate.
+var DefaultCadences = map[string]Cadence{
+	"ticketmaster": {Near: 5 * time.Minute, Day: 30 * time.Minute, Week: 2 * time.Hour, Far: 12 * time.Hour},
+	"oddsblaze":    {Near: 10 * time.Second, Day: time.Minute, Week: 5 * time.Minute, Far: 30 * time.Minute},
+}
+
+const (
+	// games that tipped off this long ago still count as near, they're being played
+	liveWindow = 3 * time.Hour
+
+	// a target whose prices move more than this between fetches, on average, is volatile
+	// and refreshed twice as often
+	volatileChange = 0.05
+
+	// how long a target whose fetch failed is left before it's tried again, at most
+	failureBackoff = time.Minute
+)
+
+// Game is one game a fetch returned. Price is whatever the provider prices it by, only its
+// relative change between fetches matters, 0 when there's none.
+type Game struct {
+	Key    string
+	TipOff time.Time
+	Price  float64
+}
+
+// Scheduler decides which target of a provider to fetch next and when, keeping every
+// provider within its quota and the retry delays it asks for. Its state is saved to a JSON
+// file after every change, so a restart neither bursts past a quota nor refetches everything.
+type Scheduler struct {
+	mu       sync.Mutex
+	path     string
+	quotas   map[string]float64
+	cadences map[string]Cadence
+	state    state
+	// now is time.Now, tests fix it
+	now func() time.Time
+}
+
+type state struct {
+	Providers map[string]*providerState `json:"providers"`
+}
+
+type providerState struct {
+	// NextCall is the earliest the provider may be called again, by quota or Retry-After
+	NextCall time.Time          `json:"next_call"`
+	Targets  map[string]*target `json:"targets"`
+}
+
+type target struct {
+	LastFetched time.Time `json:"last_fetched"`
+	// RetryAt is when a target whose last fetch failed may be tried again
+	RetryAt    time.Time          `json:"retry_at"`
+	NextTipOff time.Time          `json:"next_tip_off"`
+	Volatility float64            `json:"volatility"`
+	Prices     map[string]float64 `json:"prices,omitempty"`
+}
+
+// Load restores the scheduler saved at path. A missing file starts it fresh, an empty path
+// keeps the state in memory only.
+func Load(path string, quotas map[string]float64, cadences map[string]Cadence) (*Scheduler, error) {
+	s := &Scheduler{
+		path:     path,
+		quotas:   quotas,
+		cadences: cadences,
+		state:    state{Providers: map[string]*providerState{}},
+		now:      time.Now,
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduler state: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		// losing the state only costs some early fetches
+		slog.Warn("discarding unreadable scheduler state", "path", path, "err", err)
+		s.state = state{}
+	}
+	if s.state.Providers == nil {
+		s.state.Providers = map[string]*providerState{}
+	}
+	return s, nil
+}
+
+// Next returns the most overdue of targets and reserves a call to provider for it. When no
+// call can be made yet it returns how long to wait instead. The target stays due until its
+// fetch is reported with Observed or Failed.
+func (s *Scheduler) Next(provider string, targets []string) (string, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	p := s.provider(provider)
+
+	var next string
+	var due time.Time
+	for _, name := range targets {
+		t := p.target(name)
+		// targets that were never fetched are due right away
+		targetDue := t.LastFetched.Add(s.interval(provider, t, now))
+		if t.RetryAt.After(targetDue) {
+			targetDue = t.RetryAt
+		}
+		if next == "" || targetDue.Before(due) {
+			next, due = name, targetDue
+		}
+	}
+	if next == "" {
+		return "", time.Minute
+	}
+	if p.NextCall.After(due) {
+		due = p.NextCall
+	}
+	if wait := due.Sub(now); wait > 0 {
+		return "", wait
+	}
+
+	p.NextCall = now.Add(s.spacing(provider))
+	s.save()
+	return next, 0
+}
+
+// Observed records the games a successful fetch of target returned, their next tip-off and
+// how much their prices moved decide when it's fetched again.
+func (s *Scheduler) Observed(provider, name string, games []Game) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	t := s.provider(provider).target(name)
+
+	var nextTipOff time.Time
+	var change float64
+	prices := map[string]float64{}
+	for _, game := range games {
+		if game.TipOff.After(now.Add(-liveWindow)) && (nextTipOff.IsZero() || game.TipOff.Before(nextTipOff)) {
+			nextTipOff = game.TipOff
+		}
+		if game.Price <= 0 {
+			continue
+		}
+		prices[game.Key] = game.Price
+		if previous := t.Prices[game.Key]; previous > 0 {
+			change = math.Max(change, math.Abs(game.Price-previous)/previous)
+		}
+	}
+
+	t.LastFetched = now
+	t.RetryAt = time.Time{}
+	t.NextTipOff = nextTipOff
+	// a moving average, so one quiet fetch doesn't undo a run of price swings
+	t.Volatility = (t.Volatility + change) / 2
+	t.Prices = prices
+	s.save()
+}
+
+// Failed records that a fetch of target failed. It's tried again after failureBackoff, or
+// sooner when it's due more often than that, rather than waiting out its whole interval
+// with data that didn't arrive.
+func (s *Scheduler) Failed(provider, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	t := s.provider(provider).target(name)
+	t.RetryAt = now.Add(min(failureBackoff, s.interval(provider, t, now)))
+	s.save()
+}
+
+// RetryAfter holds off every call to provider for d, after it turned a request away.
+func (s *Scheduler) RetryAfter(provider string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.provider(provider)
+	if until := s.now().Add(d); until.After(p.NextCall) {
+		p.NextCall = until
+	}
+	s.save()
+}
+
+// interval is how long after its last fetch target t is due again.
+func (s *Scheduler) interval(provider string, t *target, now time.Time) time.Duration {
+	cadence := s.cadences[provider]
+
+	var interval time.Duration
+	untilTipOff := t.NextTipOff.Sub(now)
+	switch {
+	case t.NextTipOff.IsZero():
+		interval = cadence.Far
+	case untilTipOff < 6*time.Hour:
+		interval = cadence.Near
+	case untilTipOff < 48*time.Hour:
+		interval = cadence.Day
+	case untilTipOff < 14*24*time.Hour:
+		interval = cadence.Week
+	default:
+		interval = cadence.Far
+	}
+	if t.Volatility > volatileChange {
+		interval /= 2
+	}
+	return interval
+}
+
+// spacing is the time between two calls to provider that keeps it within its quota.
+// Providers without a quota aren't spaced.
+func (s *Scheduler) spacing(provider string) time.Duration {
+	quota := s.quotas[provider]
+	if quota <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Minute) / quota)
+}
+
+func (s *Scheduler) provider(name string) *providerState {
+	p, ok := s.state.Providers[name]
+	if !ok {
+		p = &providerState{}
+		s.state.Providers[name] = p
+	}
+	if p.Targets == nil {
+		p.Targets = map[string]*target{}
+	}
+	return p
+}
+
+func (p *providerState) target(name string) *target {
+	t, ok := p.Targets[name]
+	if !ok {
+		t = &target{}
+		p.Targets[name] = t
+	}
+	return t
+}
+
+// save writes the state next to path and renames it into place, so a crash mid-write
+// leaves the previous state readable. Failing to save is logged, not fatal.
+func (s *Scheduler) save() {
+	if s.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		slog.Error("failed to encode scheduler state", "err", err)
+		return
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		slog.Error("failed to save scheduler state", "path", s.path, "err", err)
+		return
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		slog.Error("failed to save scheduler state", "path", s.path, "err", err)
+	}
+}
+
+// ParseQuotas reads calls per minute like "ticketmaster=3.5,oddsblaze=10" on top of defaults.
+// Providers that aren't mentioned keep their default, 0 lifts a provider's quota.
+func ParseQuotas(s string, defaults map[string]float64) (map[string]float64, error) {
+	quotas := map[string]float64{}
+	for provider, quota := range defaults {
+		quotas[provider] = quota
+	}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		provider, value, ok := strings.Cut(pair, "=")
+		provider = strings.TrimSpace(provider)
+		if !ok || provider == "" {
+			return nil, fmt.Errorf("invalid quota %q, expected provider=calls per minute", pair)
+		}
+		quota, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || quota < 0 {
+			return nil, fmt.Errorf("invalid quota for %s: %q", provider, value)
+		}
+		if quota == 0 {
+			delete(quotas, provider)
+			continue
+		}
+		quotas[provider] = quota
+	}
+	return quotas, nil
+}
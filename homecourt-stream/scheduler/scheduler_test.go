@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 11, 20, 18, 0, 0, 0, time.UTC)
+
+var testCadence = Cadence{Near: 10 * time.Second, Day: time.Minute, Week: 5 * time.Minute, Far: 30 * time.Minute}
+
+// newTestScheduler returns a scheduler for provider "test", whose clock only moves when the
+// returned function advances it.
+func newTestScheduler(t *testing.T, path string, quota float64) (*Scheduler, func(time.Duration)) {
+	t.Helper()
+	s, err := Load(path, map[string]float64{"test": quota}, map[string]Cadence{"test": testCadence})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := start
+	s.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+// expectNext fails the test unless Next picks want, or has to wait for wait when want is "".
+func expectNext(t *testing.T, s *Scheduler, targets []string, want string, wait time.Duration) {
+	t.Helper()
+	got, gotWait := s.Next("test", targets)
+	if got != want || gotWait != wait {
+		t.Fatalf("Next = %q, %s, want %q, %s", got, gotWait, want, wait)
+	}
+}
+
+func TestQuotaSpacing(t *testing.T) {
+	// 10 calls a minute, one every 6 seconds
+	s, advance := newTestScheduler(t, "", 10)
+	targets := []string{"a", "b", "c"}
+
+	expectNext(t, s, targets, "a", 0)
+	expectNext(t, s, targets, "", 6*time.Second)
+	advance(5 * time.Second)
+	expectNext(t, s, targets, "", time.Second)
+	advance(time.Second)
+	// a is still due until it's reported, and was reserved first
+	expectNext(t, s, targets, "a", 0)
+	s.Observed("test", "a", nil)
+	advance(6 * time.Second)
+	expectNext(t, s, targets, "b", 0)
+
+	if got := s.spacing("unlimited"); got != 0 {
+		t.Errorf("spacing without a quota = %s, want 0", got)
+	}
+}
+
+func TestCadence(t *testing.T) {
+	tests := []struct {
+		name   string
+		tipOff time.Duration // from the fetch, 0 for no game
+		want   time.Duration
+	}{
+		{"no game", 0, testCadence.Far},
+		{"tip-off in an hour", time.Hour, testCadence.Near},
+		{"under way", -2 * time.Hour, testCadence.Near},
+		{"finished", -4 * time.Hour, testCadence.Far},
+		{"tomorrow", 24 * time.Hour, testCadence.Day},
+		{"next week", 5 * 24 * time.Hour, testCadence.Week},
+		{"next month", 30 * 24 * time.Hour, testCadence.Far},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, advance := newTestScheduler(t, "", 0)
+			var games []Game
+			if tt.tipOff != 0 {
+				games = []Game{{Key: "game", TipOff: start.Add(tt.tipOff)}}
+			}
+			s.Observed("test", "team", games)
+
+			expectNext(t, s, []string{"team"}, "", tt.want)
+			advance(tt.want)
+			expectNext(t, s, []string{"team"}, "team", 0)
+		})
+	}
+}
+
+func TestVolatileTargetsAreFetchedTwiceAsOften(t *testing.T) {
+	s, advance := newTestScheduler(t, "", 0)
+	tipOff := start.Add(24 * time.Hour)
+
+	s.Observed("test", "team", []Game{{Key: "game", TipOff: tipOff, Price: 100}})
+	expectNext(t, s, []string{"team"}, "", testCadence.Day)
+
+	advance(testCadence.Day)
+	// a 50% move puts the average at 0.25, well past volatileChange
+	s.Observed("test", "team", []Game{{Key: "game", TipOff: tipOff, Price: 150}})
+	expectNext(t, s, []string{"team"}, "", testCadence.Day/2)
+
+	// the average halves with every quiet fetch: 0.125, 0.0625, 0.03125
+	for i := 0; i < 3; i++ {
+		advance(testCadence.Day)
+		s.Observed("test", "team", []Game{{Key: "game", TipOff: tipOff, Price: 150}})
+	}
+	expectNext(t, s, []string{"team"}, "", testCadence.Day)
+}
+
+func TestFailedBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		tipOff time.Duration
+		want   time.Duration
+	}{
+		// due every 30 minutes, retried after failureBackoff
+		{"far", 0, failureBackoff},
+		// due every 10 seconds, retried on its own cadence rather than later
+		{"near", time.Hour, testCadence.Near},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, advance := newTestScheduler(t, "", 0)
+			var games []Game
+			if tt.tipOff != 0 {
+				games = []Game{{Key: "game", TipOff: start.Add(tt.tipOff)}}
+			}
+			s.Observed("test", "team", games)
+			advance(testCadence.Far)
+			expectNext(t, s, []string{"team"}, "team", 0)
+
+			s.Failed("test", "team")
+			expectNext(t, s, []string{"team"}, "", tt.want)
+			advance(tt.want)
+			expectNext(t, s, []string{"team"}, "team", 0)
+		})
+	}
+
+	t.Run("never fetched", func(t *testing.T) {
+		s, _ := newTestScheduler(t, "", 0)
+		expectNext(t, s, []string{"team"}, "team", 0)
+		s.Failed("test", "team")
+		expectNext(t, s, []string{"team"}, "", failureBackoff)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	s, advance := newTestScheduler(t, "", 10)
+	targets := []string{"a", "b"}
+
+	expectNext(t, s, targets, "a", 0)
+	s.RetryAfter("test", 2*time.Minute)
+	expectNext(t, s, targets, "", 2*time.Minute)
+	// a shorter Retry-After doesn't cut the longer one short
+	s.RetryAfter("test", time.Second)
+	expectNext(t, s, targets, "", 2*time.Minute)
+
+	advance(2 * time.Minute)
+	expectNext(t, s, targets, "a", 0)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduler-state.json")
+	s, advance := newTestScheduler(t, path, 10)
+
+	expectNext(t, s, []string{"a", "b"}, "a", 0)
+	s.Observed("test", "a", []Game{{Key: "game", TipOff: start.Add(time.Hour), Price: 80}})
+	s.RetryAfter("test", time.Minute)
+	advance(30 * time.Second)
+
+	restored, advanceRestored := newTestScheduler(t, path, 10)
+	advanceRestored(30 * time.Second)
+	// same wait for the provider, b still never fetched, and a kept its near cadence and prices
+	expectNext(t, restored, []string{"a", "b"}, "", 30*time.Second)
+	advanceRestored(30 * time.Second)
+	expectNext(t, restored, []string{"a", "b"}, "b", 0)
+	a := restored.state.Providers["test"].Targets["a"]
+	if !a.LastFetched.Equal(start) || !a.NextTipOff.Equal(start.Add(time.Hour)) || a.Prices["game"] != 80 {
+		t.Errorf("restored target a = %+v", a)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary state file left behind: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fresh, _ := newTestScheduler(t, path, 10)
+	expectNext(t, fresh, []string{"a", "b"}, "a", 0)
+
+	missing, _ := newTestScheduler(t, filepath.Join(t.TempDir(), "missing.json"), 10)
+	expectNext(t, missing, []string{"a", "b"}, "a", 0)
+}
+
+func TestParseQuotas(t *testing.T) {
+	quotas, err := ParseQuotas("ticketmaster=5, oddsblaze=0,new=2", DefaultQuotas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quotas) != 2 || quotas["ticketmaster"] != 5 || quotas["new"] != 2 {
+		t.Errorf("ParseQuotas = %v, want ticketmaster=5 and new=2", quotas)
+	}
+	if DefaultQuotas["ticketmaster"] != 3.5 {
+		t.Errorf("ParseQuotas changed the defaults to %v", DefaultQuotas)
+	}
+
+	for _, s := range []string{"ticketmaster", "=5", "oddsblaze=fast", "oddsblaze=-1"} {
+		if _, err := ParseQuotas(s, DefaultQuotas); err == nil {
+			t.Errorf("ParseQuotas(%q) succeeded, want an error", s)
+		}
+	}
+}